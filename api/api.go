@@ -2,7 +2,8 @@ package api
 
 import (
 	"vacabulary/pkg/hasher"
-	"vacabulary/pkg/s3"
+	"vacabulary/pkg/quiz"
+	"vacabulary/pkg/storage"
 	"vacabulary/pkg/token"
 	"vacabulary/pkg/translator"
 	"vacabulary/repositories/elastic"
@@ -12,32 +13,70 @@ import (
 )
 
 type App struct {
-	userRepo       postgres.Users
-	wordRepo       elastic.Words
-	collectionRepo postgres.Collections
-
-	tokenService      token.TokenService
-	translatorManager translator.TranslatorManager
-	s3Manager         s3.S3Manager
-	hasher            hasher.Hasher
+	userRepo               postgres.Users
+	wordRepo               elastic.Words
+	collectionRepo         postgres.Collections
+	studySessionRepo       postgres.StudySessions
+	collectionMemberRepo   postgres.CollectionMembers
+	collectionDeletionRepo postgres.CollectionDeletions
+	wordHistoryRepo        postgres.WordHistories
+	exportJobRepo          postgres.ExportJobs
+	translationJobRepo     postgres.TranslationJobs
+
+	tokenService  token.TokenService
+	translator    translator.Translator
+	storage       storage.Storage
+	hasher        hasher.Hasher
+	quizGenerator quiz.QuizGenerator
+
+	exportJobsQueue      chan struct{}
+	translationJobsQueue chan struct{}
 }
 
-func NewApp(userRepo postgres.Users, collectionRepo postgres.Collections, wordRepo elastic.Words, tokenService token.TokenService, translatorManager translator.TranslatorManager, s3Manager s3.S3Manager, hasher hasher.Hasher) App {
+func NewApp(userRepo postgres.Users, collectionRepo postgres.Collections, wordRepo elastic.Words, studySessionRepo postgres.StudySessions, collectionMemberRepo postgres.CollectionMembers, collectionDeletionRepo postgres.CollectionDeletions, wordHistoryRepo postgres.WordHistories, exportJobRepo postgres.ExportJobs, translationJobRepo postgres.TranslationJobs, tokenService token.TokenService, translator translator.Translator, storage storage.Storage, hasher hasher.Hasher, quizGenerator quiz.QuizGenerator) App {
 	return App{
-		userRepo:       userRepo,
-		wordRepo:       wordRepo,
-		collectionRepo: collectionRepo,
-
-		tokenService:      tokenService,
-		translatorManager: translatorManager,
-		s3Manager:         s3Manager,
-		hasher:            hasher,
+		userRepo:               userRepo,
+		wordRepo:               wordRepo,
+		collectionRepo:         collectionRepo,
+		studySessionRepo:       studySessionRepo,
+		collectionMemberRepo:   collectionMemberRepo,
+		collectionDeletionRepo: collectionDeletionRepo,
+		wordHistoryRepo:        wordHistoryRepo,
+		exportJobRepo:          exportJobRepo,
+		translationJobRepo:     translationJobRepo,
+
+		tokenService:  tokenService,
+		translator:    translator,
+		storage:       storage,
+		hasher:        hasher,
+		quizGenerator: quizGenerator,
+
+		exportJobsQueue:      make(chan struct{}, 1),
+		translationJobsQueue: make(chan struct{}, 1),
 	}
 }
 
 func (a *App) AttachEndpoints(gr *gin.Engine) {
 	a.InjectWords(gr)
+	a.InjectWordHistory(gr)
+	a.InjectImport(gr)
+	a.InjectExtract(gr)
+	a.InjectInterop(gr)
+	a.InjectExports(gr)
+	a.InjectArchive(gr)
+	a.InjectKindle(gr)
+	a.InjectTranslations(gr)
+	a.InjectReview(gr)
+	a.InjectTags(gr)
+	a.InjectTransfer(gr)
+	a.InjectMembers(gr)
 	a.InjectUsers(gr)
 	a.InjectCollections(gr)
+	a.InjectCollectionDeletions(gr)
+	a.InjectTrash(gr)
+	a.InjectCatalog(gr)
+	a.InjectQuiz(gr)
+	a.InjectSessions(gr)
 	a.InjectStatistic(gr)
+	a.InjectPersonalStatistic(gr)
 }
@@ -0,0 +1,368 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"vacabulary/config"
+	el "vacabulary/db/elastic"
+	"vacabulary/models"
+	"vacabulary/repositories/elastic"
+	"vacabulary/repositories/postgres"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	archiveFormatJson = "json"
+	archiveFormatZip  = "zip"
+
+	// archiveFileName is the name of json file inside of zip archive
+	archiveFileName = "archive.json"
+
+	// maxArchiveSize limits uploaded and unpacked archive, 100mb
+	maxArchiveSize = 100 << 20
+)
+
+var (
+	errAccountNotEmpty = errors.New("archive can be imported only into account without collections")
+)
+
+func (a *App) InjectArchive(gr *gin.Engine) {
+	archive := gr.Group("/user/archive", a.authorizeRequest)
+
+	archive.GET("", a.exportAccountArchive)
+	archive.POST("", a.importAccountArchive)
+}
+
+// exportAccountArchive returns user, settings and all collections with words, ?format=zip packs json into zip
+func (a *App) exportAccountArchive(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", archiveFormatJson)
+	if format != archiveFormatJson && format != archiveFormatZip {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("format must be one of json, zip").Error())
+		return
+	}
+
+	contextUser := a.getContextUser(ctx)
+
+	user, err := a.userRepo.GetById(contextUser.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if user == nil {
+		newErrorResponse(ctx, http.StatusNotFound, errors.New("user not found").Error())
+		return
+	}
+
+	collections, err := a.collectionRepo.GetByOwnerId(user.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	for i := range collections {
+		words, err := a.wordRepo.GetAllWords(elastic.CollectionWordsOperationCtx{UserId: user.Id, CollectionId: collections[i].Id})
+		if err != nil {
+			newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if words == nil {
+			words = []models.Word{}
+		}
+		collections[i].Words = words
+	}
+
+	archive := models.AccountArchive{
+		Version:    models.AccountArchiveVersion,
+		ExportedAt: time.Now(),
+		User: models.ArchiveUser{
+			Name:      user.Name,
+			Email:     user.Email,
+			CreatedAt: user.CreatedAt,
+		},
+		Settings:    user.Settings,
+		Collections: collections,
+	}
+
+	data, err := json.Marshal(archive)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	fileName := fmt.Sprintf("vocabulary-%s", archive.ExportedAt.Format("2006-01-02"))
+
+	if format == archiveFormatZip {
+		data, err = zipArchive(data)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		ctx.Writer.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=%s.zip", fileName))
+		ctx.Data(http.StatusOK, "application/zip", data)
+		return
+	}
+
+	ctx.Writer.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=%s.json", fileName))
+	ctx.Data(http.StatusOK, "application/json", data)
+}
+
+func zipArchive(data []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+
+	w, err := zipWriter.Create(archiveFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = w.Write(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = zipWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// importAccountArchive restores archive from multipart file or json body.
+// Collections get new ids, so words are moved to them and aliases are created again.
+func (a *App) importAccountArchive(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxArchiveSize+1<<20)
+
+	var data []byte
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		var ok bool
+		data, ok = readFormFile(ctx, maxArchiveSize)
+		if !ok {
+			return
+		}
+	} else {
+		var err error
+		data, err = io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	archive, err := readAccountArchive(data)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	collections, err := a.collectionRepo.GetByOwnerId(user.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if len(collections) > 0 {
+		newErrorResponse(ctx, http.StatusConflict, errAccountNotEmpty.Error())
+		return
+	}
+
+	result, err := a.restoreAccountArchive(user.Id, archive)
+	if err != nil {
+		if errors.Is(err, postgres.ErrCollectionNameExists) {
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success",
+		"result":  result,
+	})
+}
+
+// readAccountArchive parses json or zip with json file and checks archive version
+func readAccountArchive(data []byte) (*models.AccountArchive, error) {
+	// zip files start with PK signature
+	if bytes.HasPrefix(data, []byte("PK")) {
+		zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+
+		var archiveFile *zip.File
+		for _, f := range zipReader.File {
+			if f.Name == archiveFileName {
+				archiveFile = f
+			}
+		}
+
+		if archiveFile == nil {
+			return nil, fmt.Errorf("zip archive has no %s", archiveFileName)
+		}
+
+		r, err := archiveFile.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		data, err = io.ReadAll(io.LimitReader(r, maxArchiveSize+1))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data) > maxArchiveSize {
+			return nil, errors.New("archive is too large")
+		}
+	}
+
+	archive := models.AccountArchive{}
+	err := json.Unmarshal(data, &archive)
+	if err != nil {
+		return nil, fmt.Errorf("archive is not valid: %w", err)
+	}
+
+	if archive.Version == 0 || archive.Version > models.AccountArchiveVersion {
+		return nil, fmt.Errorf("archive version %d is not supported", archive.Version)
+	}
+
+	return &archive, nil
+}
+
+// restoreAccountArchive creates collections and words of the archive for the user,
+// created collections are deleted when import fails, so it can be repeated
+func (a *App) restoreAccountArchive(userId uint64, archive *models.AccountArchive) (*models.AccountArchiveImport, error) {
+	result := &models.AccountArchiveImport{
+		CollectionIds: map[uint64]uint64{},
+	}
+
+	created := []models.Collection{}
+	rollback := func() {
+		for _, c := range created {
+			_, err := a.deleteCollectionCascade(c)
+			if err != nil {
+				fmt.Printf("failed to delete imported collection %d: %s\n", c.Id, err.Error())
+			}
+		}
+	}
+
+	elClient := el.NewElasticClient(config.Config.Elastic)
+
+	for _, c := range archive.Collections {
+		name := strings.TrimSpace(c.Name)
+		if name == "" {
+			rollback()
+			return nil, fmt.Errorf("collection %d has empty name", c.Id)
+		}
+
+		schedulerSettings, err := normalizeSchedulerSettings(c.SchedulerSettings)
+		if err != nil {
+			rollback()
+			return nil, err
+		}
+
+		createdAt := c.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+
+		// publication is not restored, collection is published again by the owner
+		collection, err := a.collectionRepo.Create(models.Collection{
+			Name:              name,
+			OwnerId:           userId,
+			LangFrom:          c.LangFrom,
+			LangTo:            c.LangTo,
+			Description:       c.Description,
+			Color:             c.Color,
+			Icon:              c.Icon,
+			Archived:          c.Archived,
+			SortOrder:         c.SortOrder,
+			CreatedAt:         createdAt,
+			SchedulerSettings: schedulerSettings,
+		})
+		if err != nil {
+			rollback()
+			return nil, err
+		}
+		created = append(created, *collection)
+
+		err = elClient.CreateCollectionAliases(userId, collection.Id)
+		if err != nil {
+			rollback()
+			return nil, err
+		}
+
+		words := []models.Word{}
+		for _, w := range c.Words {
+			if strings.TrimSpace(w.Word) == "" {
+				continue
+			}
+
+			w.Id = ""
+			w.CollectionId = collection.Id
+			w.DeletedAt = nil
+			words = append(words, w)
+		}
+
+		if len(words) > 0 {
+			err = a.wordRepo.BulkImport(words, elastic.CollectionWordsOperationCtx{UserId: userId, CollectionId: collection.Id})
+			if err != nil {
+				rollback()
+				return nil, err
+			}
+		}
+
+		result.CollectionIds[c.Id] = collection.Id
+		result.Collections++
+		result.Words += uint64(len(words))
+	}
+
+	a.restoreArchiveSettings(userId, archive.Settings)
+
+	return result, nil
+}
+
+// restoreArchiveSettings copies settings, failed settings are skipped because collections are already imported
+func (a *App) restoreArchiveSettings(userId uint64, settings *models.UserSettings) {
+	if settings == nil {
+		return
+	}
+
+	if settings.Language != "" {
+		err := a.userRepo.UpdateUserLanguage(settings.Language, userId)
+		if err != nil {
+			fmt.Printf("failed to restore language of user %d: %s\n", userId, err.Error())
+		}
+	}
+
+	if settings.Timezone != "" {
+		if _, err := time.LoadLocation(settings.Timezone); err == nil {
+			err = a.userRepo.UpdateUserTimezone(settings.Timezone, userId)
+			if err != nil {
+				fmt.Printf("failed to restore timezone of user %d: %s\n", userId, err.Error())
+			}
+		}
+	}
+
+	if settings.DailyGoal > 0 {
+		err := a.userRepo.UpdateUserDailyGoal(settings.DailyGoal, userId)
+		if err != nil {
+			fmt.Printf("failed to restore daily goal of user %d: %s\n", userId, err.Error())
+		}
+	}
+}
@@ -0,0 +1,445 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"vacabulary/config"
+	el "vacabulary/db/elastic"
+	"vacabulary/models"
+	"vacabulary/repositories/elastic"
+	"vacabulary/repositories/postgres"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errCollectionNotPublic = errors.New("collection is not public")
+)
+
+func (a *App) InjectCatalog(gr *gin.Engine) {
+	catalog := gr.Group("/catalog")
+
+	catalog.GET("", a.getCatalog)
+	catalog.GET(":id", a.idParam("id"), a.getCatalogCollection)
+	catalog.POST(":id/clone", a.authorizeRequest, a.idParam("id"), a.cloneCatalogCollection)
+
+	collections := gr.Group("/collection", a.authorizeRequest)
+
+	collections.PUT(":id/publish", a.idParam("id"), a.collectionAccess("id", collectionManage), a.publishCollection)
+}
+
+type getCatalogResponse struct {
+	Collections      []models.CatalogCollection `json:"collections"`
+	TotalCollections uint64                     `json:"totalCollections"`
+}
+
+func (a *App) getCatalog(ctx *gin.Context) {
+	filter, err := getCatalogFilterParams(ctx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var size, page uint64
+	if ctx.Query("size") != "" || ctx.Query("page") != "" {
+		size, page, err = getPaginationParams(ctx)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusBadRequest, errors.New("pagination params not valid").Error())
+			return
+		}
+	}
+
+	var collections []models.Collection
+	var total uint64
+	if filter.SortBy == models.CatalogSortByWords || filter.MinWords > 0 {
+		collections, total, err = a.getPublicByWordsCount(filter, size, page)
+	} else {
+		collections, total, err = a.collectionRepo.GetPublic(filter, size, page)
+	}
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	catalogCollections, err := a.toCatalogCollections(collections)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getCatalogResponse{
+		Collections:      catalogCollections,
+		TotalCollections: total,
+	})
+}
+
+// getPublicByWordsCount returns page of public collections filtered or sorted by words count,
+// words are stored in elastic, so counts of all matched collections are loaded by one request
+func (a *App) getPublicByWordsCount(filter models.CatalogFilter, size, page uint64) ([]models.Collection, uint64, error) {
+	collections, _, err := a.collectionRepo.GetPublic(filter, 0, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	counts, err := a.getWordsCounts(collections)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filtered := []models.Collection{}
+	for _, c := range collections {
+		if counts[c.Id] >= filter.MinWords {
+			filtered = append(filtered, c)
+		}
+	}
+
+	if filter.SortBy == models.CatalogSortByWords {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return counts[filtered[i].Id] > counts[filtered[j].Id]
+		})
+	}
+
+	total := uint64(len(filtered))
+	if size == 0 {
+		return filtered, total, nil
+	}
+
+	from := size * (page - 1)
+	if from > total {
+		from = total
+	}
+	to := from + size
+	if to > total {
+		to = total
+	}
+
+	return filtered[from:to], total, nil
+}
+
+func getCatalogFilterParams(ctx *gin.Context) (models.CatalogFilter, error) {
+	filter := models.CatalogFilter{
+		LangFrom: ctx.Query("langFrom"),
+		LangTo:   ctx.Query("langTo"),
+		Name:     strings.TrimSpace(ctx.Query("name")),
+		SortBy:   ctx.Query("sortBy"),
+	}
+
+	if minWordsStr := ctx.Query("minWords"); minWordsStr != "" {
+		minWords, err := strconv.ParseUint(minWordsStr, 10, 64)
+		if err != nil {
+			return filter, errors.New("minWords not valid")
+		}
+		filter.MinWords = minWords
+	}
+
+	if filter.SortBy == "" {
+		filter.SortBy = models.CatalogSortByPublishedAt
+	}
+
+	if filter.SortBy != models.CatalogSortByPublishedAt && filter.SortBy != models.CatalogSortByName && filter.SortBy != models.CatalogSortByWords {
+		return filter, errors.New("sortBy must be one of publishedAt, name, words")
+	}
+
+	return filter, nil
+}
+
+// toCatalogCollections adds words count and owner name to public collections
+func (a *App) toCatalogCollections(collections []models.Collection) ([]models.CatalogCollection, error) {
+	counts, err := a.getWordsCounts(collections)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerIds := []uint64{}
+	seenOwners := map[uint64]bool{}
+	for _, c := range collections {
+		if !seenOwners[c.OwnerId] {
+			seenOwners[c.OwnerId] = true
+			ownerIds = append(ownerIds, c.OwnerId)
+		}
+	}
+
+	owners, err := a.userRepo.GetByIds(ownerIds)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerNames := map[uint64]string{}
+	for _, o := range owners {
+		ownerNames[o.Id] = o.Name
+	}
+
+	catalogCollections := []models.CatalogCollection{}
+	for _, c := range collections {
+		catalogCollections = append(catalogCollections, models.CatalogCollection{
+			Collection: c,
+			WordsCount: counts[c.Id],
+			OwnerName:  ownerNames[c.OwnerId],
+		})
+	}
+
+	return catalogCollections, nil
+}
+
+// getWordsCounts returns count of words by collection id
+func (a *App) getWordsCounts(collections []models.Collection) (map[uint64]uint64, error) {
+	wordsCtxs := []elastic.CollectionWordsOperationCtx{}
+	for _, c := range collections {
+		wordsCtxs = append(wordsCtxs, elastic.CollectionWordsOperationCtx{UserId: c.OwnerId, CollectionId: c.Id})
+	}
+
+	wordsPerCollection, err := a.wordRepo.GetCountOfWordsInCollections(wordsCtxs)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[uint64]uint64{}
+	for _, w := range wordsPerCollection {
+		counts[w.CollectionId] = w.Count
+	}
+
+	return counts, nil
+}
+
+type getCatalogCollectionResponse struct {
+	Collection models.CatalogCollection `json:"collection"`
+	Words      []models.Word            `json:"words"`
+}
+
+func (a *App) getCatalogCollection(ctx *gin.Context) {
+	id := ctx.GetUint64("id")
+	if id == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	collection, err := a.getPublicCollection(id)
+	if err != nil {
+		a.catalogErrorResponse(ctx, err)
+		return
+	}
+
+	// all words are returned without pagination params
+	wordsCtx := elastic.CollectionWordsOperationCtx{UserId: collection.OwnerId, CollectionId: collection.Id}
+
+	var words []models.Word
+	if ctx.Query("size") != "" || ctx.Query("page") != "" {
+		size, page, err := getPaginationParams(ctx)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		words, _, err = a.wordRepo.GetAll(size, page, wordsCtx)
+	} else {
+		words, err = a.wordRepo.GetAllWords(wordsCtx)
+	}
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	catalogCollections, err := a.toCatalogCollections([]models.Collection{*collection})
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// learning progress of the owner is not shared
+	for i := range words {
+		words[i].Progress = models.WordProgress{}
+	}
+
+	ctx.JSON(http.StatusOK, getCatalogCollectionResponse{
+		Collection: catalogCollections[0],
+		Words:      words,
+	})
+}
+
+type cloneCatalogCollectionInp struct {
+	Name string `json:"name"`
+}
+
+func (a *App) cloneCatalogCollection(ctx *gin.Context) {
+	id := ctx.GetUint64("id")
+	if id == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	// body is optional, the name of public collection is used by default
+	var input cloneCatalogCollectionInp
+	if ctx.Request.ContentLength > 0 {
+		err := ctx.BindJSON(&input)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	source, err := a.getPublicCollection(id)
+	if err != nil {
+		a.catalogErrorResponse(ctx, err)
+		return
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		name = source.Name
+	}
+
+	user := a.getContextUser(ctx)
+
+	collection, err := a.collectionRepo.GetByOwnerAndName(user.Id, name)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if collection != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("collection with such name already exists").Error())
+		return
+	}
+
+	words, err := a.wordRepo.GetAllWords(elastic.CollectionWordsOperationCtx{UserId: source.OwnerId, CollectionId: source.Id})
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	collection, err = a.collectionRepo.Create(models.Collection{
+		Name:              name,
+		OwnerId:           user.Id,
+		LangFrom:          source.LangFrom,
+		LangTo:            source.LangTo,
+		Description:       source.Description,
+		CreatedAt:         time.Now(),
+		SchedulerSettings: source.SchedulerSettings,
+	})
+	if err != nil {
+		// name can be taken by concurrent request after the check
+		if errors.Is(err, postgres.ErrCollectionNameExists) {
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	elClient := el.NewElasticClient(config.Config.Elastic)
+	err = elClient.CreateCollectionAliases(user.Id, collection.Id)
+	if err != nil {
+		a.rollbackImportCollection(collection)
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	clonedWords := []models.Word{}
+	for _, w := range words {
+		w.Id = ""
+		w.CollectionId = collection.Id
+		w.Progress = models.WordProgress{}
+
+		clonedWords = append(clonedWords, w)
+	}
+
+	if len(clonedWords) > 0 {
+		err = a.wordRepo.BulkCreate(clonedWords, elastic.CollectionWordsOperationCtx{UserId: user.Id, CollectionId: collection.Id})
+		if err != nil {
+			a.rollbackImportCollection(collection)
+			newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":     "success",
+		"collection":  collection,
+		"clonedWords": len(clonedWords),
+	})
+}
+
+type publishCollectionInp struct {
+	IsPublic    bool   `json:"isPublic"`
+	Description string `json:"description"`
+}
+
+func (a *App) publishCollection(ctx *gin.Context) {
+	var input publishCollectionInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collection := getContextCollection(ctx)
+
+	description := strings.TrimSpace(input.Description)
+	if input.IsPublic && description == "" {
+		description = collection.Description
+	}
+
+	if input.IsPublic && description == "" {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("description can't be empty for public collection").Error())
+		return
+	}
+
+	if input.IsPublic && (collection.LangFrom == "" || collection.LangTo == "") {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("lang from and lang to can't be empty").Error())
+		return
+	}
+
+	var publishedAt *time.Time
+	if input.IsPublic {
+		publishedAt = collection.PublishedAt
+		if publishedAt == nil {
+			now := time.Now()
+			publishedAt = &now
+		}
+	}
+
+	if description == "" {
+		description = collection.Description
+	}
+
+	err = a.collectionRepo.UpdatePublication(collection.Id, input.IsPublic, description, publishedAt)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success update",
+	})
+}
+
+func (a *App) getPublicCollection(id uint64) (*models.Collection, error) {
+	collection, err := a.collectionRepo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if collection == nil {
+		return nil, errCollectionNotFound
+	}
+
+	if !collection.IsPublic {
+		return nil, errCollectionNotPublic
+	}
+
+	return collection, nil
+}
+
+// catalogErrorResponse hides private collections as not found
+func (a *App) catalogErrorResponse(ctx *gin.Context, err error) {
+	if errors.Is(err, errCollectionNotFound) || errors.Is(err, errCollectionNotPublic) {
+		newErrorResponse(ctx, http.StatusNotFound, errCollectionNotFound.Error())
+		return
+	}
+
+	newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+}
@@ -1,35 +1,48 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 	"vacabulary/config"
 	el "vacabulary/db/elastic"
 	"vacabulary/models"
 	pdfgenerator "vacabulary/pkg/pdfGenerator"
+	"vacabulary/pkg/scheduler"
 	"vacabulary/repositories/elastic"
+	"vacabulary/repositories/postgres"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	maxCollectionIconLength = 64
+)
+
 var (
 	errEmptyQueryText = errors.New("empty query text")
+
+	collectionColorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
 )
 
 func (a *App) InjectCollections(gr *gin.Engine) {
 	collections := gr.Group("/collection", a.authorizeRequest)
 
-	collections.POST("", a.createCollection)                                  // OK
-	collections.GET("/all", a.getAllCollections)                              // OK
-	collections.GET(":id", a.idParam("id"), a.getCollection)                  // OK
-	collections.PUT(":id", a.idParam("id"), a.updateCollection)               // OK
-	collections.DELETE(":id", a.idParam("id"), a.deleteCollection)            // OK
-	collections.GET(":id/search", a.idParam("id"), a.searchWordsInCollection) // OK
+	collections.POST("", a.createCollection)                                                                            // OK
+	collections.GET("/all", a.getAllCollections)                                                                        // OK
+	collections.GET(":id", a.idParam("id"), a.collectionAccess("id", collectionRead), a.getCollection)                  // OK
+	collections.PUT(":id", a.idParam("id"), a.collectionAccess("id", collectionManage), a.updateCollection)             // OK
+	collections.DELETE(":id", a.idParam("id"), a.collectionAccess("id", collectionManage), a.deleteCollection)          // OK
+	collections.GET(":id/search", a.idParam("id"), a.collectionAccess("id", collectionRead), a.searchWordsInCollection) // OK
 
-	collections.POST(":id/generatePdf", a.idParam("id"), a.generatePdfCollection)
+	collections.POST(":id/generatePdf", a.idParam("id"), a.collectionAccess("id", collectionRead), a.generatePdfCollection)
+	collections.PUT(":id/scheduler", a.idParam("id"), a.collectionAccess("id", collectionManage), a.updateCollectionScheduler)
 }
 
 type createCollectionInp struct {
@@ -37,6 +50,13 @@ type createCollectionInp struct {
 	OwnerId  string `json:"ownerId"`
 	LangFrom string `json:"langFrom"`
 	LangTo   string `json:"langTo"`
+
+	Description string `json:"description"`
+	Color       string `json:"color"`
+	Icon        string `json:"icon"`
+	SortOrder   int64  `json:"sortOrder"`
+
+	SchedulerSettings models.SchedulerSettings `json:"schedulerSettings"`
 }
 
 func (a *App) createCollection(ctx *gin.Context) {
@@ -47,7 +67,21 @@ func (a *App) createCollection(ctx *gin.Context) {
 		return
 	}
 
-	collection, err := a.collectionRepo.GetByName(input.Name)
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("name can't be empty").Error())
+		return
+	}
+
+	err = validateCollectionAppearance(input.Color, input.Icon)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	collection, err := a.collectionRepo.GetByOwnerAndName(user.Id, input.Name)
 	if err != nil {
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
@@ -71,17 +105,30 @@ func (a *App) createCollection(ctx *gin.Context) {
 		return
 	}
 
-	user := a.getContextUser(ctx)
+	schedulerSettings, err := normalizeSchedulerSettings(input.SchedulerSettings)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// create collection
 	collection, err = a.collectionRepo.Create(models.Collection{
-		Name:      input.Name,
-		OwnerId:   user.Id,
-		LangFrom:  input.LangFrom,
-		LangTo:    input.LangTo,
-		CreatedAt: time.Now(),
+		Name:              input.Name,
+		OwnerId:           user.Id,
+		LangFrom:          input.LangFrom,
+		LangTo:            input.LangTo,
+		Description:       strings.TrimSpace(input.Description),
+		Color:             input.Color,
+		Icon:              input.Icon,
+		SortOrder:         input.SortOrder,
+		CreatedAt:         time.Now(),
+		SchedulerSettings: schedulerSettings,
 	})
 	if err != nil {
+		if errors.Is(err, postgres.ErrCollectionNameExists) {
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -104,6 +151,13 @@ type getAllCollectionsResponse struct {
 }
 
 func (a *App) getAllCollections(ctx *gin.Context) {
+	// archived param shows only archived or only active collections, all are returned without it
+	archivedStr := ctx.Query("archived")
+	if archivedStr != "" && archivedStr != "true" && archivedStr != "false" {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("archived must be true or false").Error())
+		return
+	}
+
 	user := a.getContextUser(ctx)
 
 	collections, err := a.collectionRepo.GetByOwnerId(user.Id)
@@ -114,7 +168,11 @@ func (a *App) getAllCollections(ctx *gin.Context) {
 
 	var collectionsWithWords []models.Collection
 	for _, c := range collections {
-		words, _, err := a.wordRepo.GetAll(0, 0, elastic.CollectionWordsOperationCtx{UserId: user.Id, CollectionId: c.Id})
+		if archivedStr != "" && strconv.FormatBool(c.Archived) != archivedStr {
+			continue
+		}
+
+		words, err := a.wordRepo.GetAllWords(elastic.CollectionWordsOperationCtx{UserId: user.Id, CollectionId: c.Id})
 		if err != nil {
 			continue
 		}
@@ -133,43 +191,39 @@ func (a *App) getAllCollections(ctx *gin.Context) {
 	})
 }
 
+// generatePdfCollection prints words of the collection, body with pdf options is optional
 func (a *App) generatePdfCollection(ctx *gin.Context) {
-	id := ctx.GetUint64("id")
-	if id == 0 {
-		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
-		return
+	// chunked body has unknown content length, so body is decoded whenever it is sent
+	var options pdfgenerator.Options
+	if ctx.Request.Body != nil && ctx.Request.Body != http.NoBody {
+		err := json.NewDecoder(ctx.Request.Body).Decode(&options)
+		if err != nil && !errors.Is(err, io.EOF) {
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
 	}
 
-	user := a.getContextUser(ctx)
-
-	collection, err := a.collectionRepo.GetById(id)
+	options, err := options.Normalize()
 	if err != nil {
 		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	words, _, err := a.wordRepo.GetAll(0, 0, elastic.CollectionWordsOperationCtx{UserId: user.Id, CollectionId: uint64(id)})
+	collection := getContextCollection(ctx)
+
+	words, err := a.wordRepo.GetAllWords(contextWordsCtx(ctx))
 	if err != nil {
-		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	contents := [][]string{}
-	for _, w := range words {
-		row := []string{}
-		row = append(row, w.Word)
-		row = append(row, w.Translation)
-
-		contents = append(contents, row)
-	}
-
-	file, err := pdfgenerator.GenerateCollectionPdf(contents, collection.Name)
+	file, err := pdfgenerator.GenerateCollectionPdf(words, collection.Name, options)
 	if err != nil {
 		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.Writer.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=%s.pdf", collection.Name))
+	ctx.Writer.Header().Add("Content-Disposition", attachmentDisposition(collection.Name+".pdf"))
 	ctx.Writer.Header().Add("Content-type", "application/pdf")
 	ctx.Writer.Write(file)
 }
@@ -179,64 +233,175 @@ type getCollectionResponse struct {
 }
 
 func (a *App) getCollection(ctx *gin.Context) {
-	id := ctx.GetUint64("id")
-	if id == 0 {
-		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+	collection := getContextCollection(ctx)
+
+	ctx.JSON(http.StatusOK, getCollectionResponse{
+		Collection: collection,
+	})
+}
+
+// updateCollectionInp changes only passed fields
+type updateCollectionInp struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Color       *string `json:"color"`
+	Icon        *string `json:"icon"`
+	Archived    *bool   `json:"archived"`
+	SortOrder   *int64  `json:"sortOrder"`
+}
+
+func (a *App) updateCollection(ctx *gin.Context) {
+	collection := *getContextCollection(ctx)
+
+	var input updateCollectionInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			newErrorResponse(ctx, http.StatusBadRequest, errors.New("name can't be empty").Error())
+			return
+		}
+
+		sameNameCollection, err := a.collectionRepo.GetByOwnerAndName(collection.OwnerId, name)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if sameNameCollection != nil && sameNameCollection.Id != collection.Id {
+			newErrorResponse(ctx, http.StatusBadRequest, postgres.ErrCollectionNameExists.Error())
+			return
+		}
+
+		collection.Name = name
+	}
+	if input.Description != nil {
+		collection.Description = strings.TrimSpace(*input.Description)
+	}
+	if input.Color != nil {
+		collection.Color = *input.Color
+	}
+	if input.Icon != nil {
+		collection.Icon = *input.Icon
+	}
+	if input.Archived != nil {
+		collection.Archived = *input.Archived
+	}
+	if input.SortOrder != nil {
+		collection.SortOrder = *input.SortOrder
+	}
+
+	err = validateCollectionAppearance(collection.Color, collection.Icon)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	collection, err := a.collectionRepo.GetById(id)
+	updatedCollection, err := a.collectionRepo.Update(&collection)
 	if err != nil {
+		if errors.Is(err, postgres.ErrCollectionNameExists) {
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, getCollectionResponse{
-		Collection: collection,
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":    "success update",
+		"collection": updatedCollection,
 	})
 }
 
-type updateCollectionInp struct {
-	Name string `json:"name"`
+// validateCollectionAppearance checks color is hex color like #1e90ff and icon is short
+func validateCollectionAppearance(color string, icon string) error {
+	if color != "" && !collectionColorRegexp.MatchString(color) {
+		return errors.New("color must be hex color like #1e90ff")
+	}
+
+	if len(icon) > maxCollectionIconLength {
+		return fmt.Errorf("icon can't be longer than %d symbols", maxCollectionIconLength)
+	}
+
+	return nil
 }
 
-func (a *App) updateCollection(ctx *gin.Context) {
-	id := ctx.GetUint64("id")
-	if id == 0 {
-		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+func (a *App) updateCollectionScheduler(ctx *gin.Context) {
+	collection := getContextCollection(ctx)
+
+	var input models.SchedulerSettings
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	var input updateCollectionInp
-	err := ctx.BindJSON(&input)
+	schedulerSettings, err := normalizeSchedulerSettings(input)
 	if err != nil {
 		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	_, err = a.collectionRepo.Update(&models.Collection{
-		Id:   id,
-		Name: input.Name,
-	})
+	err = a.collectionRepo.UpdateSchedulerSettings(collection.Id, schedulerSettings)
 	if err != nil {
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	ctx.JSON(http.StatusOK, map[string]interface{}{
-		"message": "success update",
+		"message":           "success update",
+		"schedulerSettings": schedulerSettings,
 	})
 }
 
+// normalizeSchedulerSettings validates settings and fills defaults
+func normalizeSchedulerSettings(settings models.SchedulerSettings) (models.SchedulerSettings, error) {
+	if settings.Type == "" {
+		settings.Type = models.SchedulerTypeSM2
+	}
+
+	switch settings.Type {
+	case models.SchedulerTypeSM2:
+		settings.LeitnerIntervals = nil
+	case models.SchedulerTypeLeitner:
+		if len(settings.LeitnerIntervals) == 0 {
+			settings.LeitnerIntervals = scheduler.DefaultLeitnerIntervals
+		}
+	}
+
+	_, err := scheduler.NewScheduler(settings)
+	if err != nil {
+		return models.SchedulerSettings{}, err
+	}
+
+	return settings, nil
+}
+
+// deleteCollection moves collection to trash, it is purged with its words after retention period
 func (a *App) deleteCollection(ctx *gin.Context) {
-	id := ctx.GetUint64("id")
-	if id == 0 {
-		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+	collection := getContextCollection(ctx)
+	deletedAt := time.Now()
+
+	// words are hidden first, so collection is not in trash while its words are still searchable
+	wordsCtx := elastic.CollectionWordsOperationCtx{UserId: collection.OwnerId, CollectionId: collection.Id}
+	err := a.wordRepo.SoftDeleteByCollectionId(deletedAt, wordsCtx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	err := a.collectionRepo.DeleteById(id)
+	err = a.collectionRepo.SoftDeleteById(collection.Id, deletedAt)
 	if err != nil {
+		restoreErr := a.wordRepo.RestoreByCollectionId(wordsCtx)
+		if restoreErr != nil {
+			fmt.Printf("failed to restore words of collection %d: %s\n", collection.Id, restoreErr.Error())
+		}
+
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -259,19 +424,11 @@ func (a *App) searchWordsInCollection(ctx *gin.Context) {
 			})
 			return
 		}
-		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
-		return
-	}
-
-	id := ctx.GetUint64("id")
-	if id == 0 {
-		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	user := a.getContextUser(ctx)
-
-	words, err := a.wordRepo.Search(*searchSettings, elastic.CollectionWordsOperationCtx{UserId: user.Id, CollectionId: id})
+	words, err := a.wordRepo.Search(*searchSettings, contextWordsCtx(ctx))
 	if err != nil {
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
@@ -304,5 +461,11 @@ func getSearchWordsInCollectionParams(ctx *gin.Context) (*models.SearchSettings,
 	}
 	searchSettings.TextForSearch = text
 
+	filter, err := getWordsFilterParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	searchSettings.WordsFilter = filter
+
 	return &searchSettings, nil
 }
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"vacabulary/config"
+	el "vacabulary/db/elastic"
+	"vacabulary/models"
+	"vacabulary/repositories/elastic"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// deletion is not retried anymore after this count of attempts
+	maxCollectionDeletionAttempts = 10
+)
+
+func (a *App) InjectCollectionDeletions(gr *gin.Engine) {
+	deletions := gr.Group("/collection/deletions", a.authorizeRequest)
+
+	deletions.GET("", a.getCollectionDeletions)
+	deletions.GET(":id", a.idParam("id"), a.getCollectionDeletion)
+	deletions.POST(":id/retry", a.idParam("id"), a.retryCollectionDeletion)
+}
+
+type getCollectionDeletionsResponse struct {
+	Deletions []models.CollectionDeletion `json:"deletions"`
+}
+
+func (a *App) getCollectionDeletions(ctx *gin.Context) {
+	user := a.getContextUser(ctx)
+
+	deletions, err := a.collectionDeletionRepo.GetByOwnerId(user.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getCollectionDeletionsResponse{
+		Deletions: deletions,
+	})
+}
+
+func (a *App) getCollectionDeletion(ctx *gin.Context) {
+	deletion, ok := a.getContextCollectionDeletion(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"deletion": deletion,
+	})
+}
+
+func (a *App) retryCollectionDeletion(ctx *gin.Context) {
+	deletion, ok := a.getContextCollectionDeletion(ctx)
+	if !ok {
+		return
+	}
+
+	if deletion.Status == models.DeletionStatusCompleted {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("collection deletion already completed").Error())
+		return
+	}
+
+	err := a.runCollectionDeletion(deletion)
+	if err != nil {
+		ctx.JSON(http.StatusAccepted, map[string]interface{}{
+			"message":  "collection data is not removed yet, deletion will be retried",
+			"deletion": deletion,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":  "success",
+		"deletion": deletion,
+	})
+}
+
+func (a *App) getContextCollectionDeletion(ctx *gin.Context) (*models.CollectionDeletion, bool) {
+	id := ctx.GetUint64("id")
+	if id == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return nil, false
+	}
+
+	user := a.getContextUser(ctx)
+
+	deletion, err := a.collectionDeletionRepo.GetById(id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	if deletion == nil || deletion.OwnerId != user.Id {
+		newErrorResponse(ctx, http.StatusNotFound, errors.New("collection deletion not found").Error())
+		return nil, false
+	}
+
+	return deletion, true
+}
+
+// deleteCollectionCascade deletes collection from postgres and then its words and alias from elasticsearch,
+// deletion is stored as failed when elasticsearch is not available and retried later
+func (a *App) deleteCollectionCascade(collection models.Collection) (*models.CollectionDeletion, error) {
+	deletion, err := a.collectionDeletionRepo.DeleteCollection(collection, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// error is saved in deletion
+	_ = a.runCollectionDeletion(deletion)
+
+	return deletion, nil
+}
+
+// runCollectionDeletion removes collection words and alias, result of the attempt is saved in deletion
+func (a *App) runCollectionDeletion(deletion *models.CollectionDeletion) error {
+	deletion.Attempts++
+
+	err := a.removeCollectionData(deletion)
+	if err != nil {
+		deletion.Status = models.DeletionStatusFailed
+		deletion.Error = err.Error()
+	} else {
+		finishedAt := time.Now()
+		deletion.Status = models.DeletionStatusCompleted
+		deletion.Error = ""
+		deletion.FinishedAt = &finishedAt
+	}
+
+	updateErr := a.collectionDeletionRepo.Update(*deletion)
+	if updateErr != nil {
+		fmt.Printf("failed to save collection deletion %d: %s\n", deletion.Id, updateErr.Error())
+	}
+
+	return err
+}
+
+func (a *App) removeCollectionData(deletion *models.CollectionDeletion) error {
+	wordsCtx := elastic.CollectionWordsOperationCtx{UserId: deletion.OwnerId, CollectionId: deletion.CollectionId}
+
+	// history is removed before words, so ids of words are still known when attempt is retried
+	wordIds, err := a.wordRepo.GetIdsByCollectionId(wordsCtx)
+	if err != nil {
+		return err
+	}
+
+	err = a.wordHistoryRepo.DeleteByWordIds(wordIds)
+	if err != nil {
+		return err
+	}
+
+	wordsRemoved, err := a.wordRepo.DeleteByCollectionId(wordsCtx)
+	deletion.WordsRemoved += wordsRemoved
+	if err != nil {
+		return err
+	}
+
+	elClient := el.NewElasticClient(config.Config.Elastic)
+	aliasRemoved, err := elClient.DeleteCollectionAliases(deletion.OwnerId, deletion.CollectionId)
+	if err != nil {
+		return err
+	}
+
+	deletion.AliasRemoved = deletion.AliasRemoved || aliasRemoved
+
+	return nil
+}
+
+// RetryCollectionDeletions periodically retries unfinished collection deletions, it blocks forever
+func (a *App) RetryCollectionDeletions(interval time.Duration) {
+	jobRunner[models.CollectionDeletion]{
+		name: "collection deletions",
+		// pending deletion created during the last interval can be still run by request
+		take: func(now time.Time) ([]models.CollectionDeletion, error) {
+			return a.collectionDeletionRepo.GetUnfinished(now.Add(-interval), maxCollectionDeletionAttempts)
+		},
+		run: func(deletion *models.CollectionDeletion) {
+			err := a.runCollectionDeletion(deletion)
+			if err != nil {
+				fmt.Printf("failed to delete data of collection %d: %s\n", deletion.CollectionId, err.Error())
+			}
+		},
+	}.Run(interval)
+}
@@ -0,0 +1,368 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+	"vacabulary/config"
+	"vacabulary/models"
+	"vacabulary/pkg/anki"
+	pdfgenerator "vacabulary/pkg/pdfGenerator"
+	"vacabulary/pkg/storage"
+	"vacabulary/pkg/wordimport"
+	"vacabulary/repositories/elastic"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// export is failed after this count of attempts
+	maxExportAttempts = 3
+	// processing job is returned to the queue when worker doesn't finish it in time
+	exportStaleTimeout = 10 * time.Minute
+	// exported files are removed after this period
+	exportRetention = 24 * time.Hour
+	// failed export is retried after this delay, it is doubled after every attempt
+	exportRetryDelay = time.Minute
+)
+
+var (
+	errExportNotFound = errors.New("export not found")
+)
+
+func (a *App) InjectExports(gr *gin.Engine) {
+	collections := gr.Group("/collection", a.authorizeRequest)
+	collections.POST(":id/exports", a.idParam("id"), a.collectionAccess("id", collectionRead), a.createExport)
+
+	exports := gr.Group("/exports")
+	exports.GET("", a.authorizeRequest, a.getExports)
+	exports.GET(":id", a.authorizeRequest, a.idParam("id"), a.getExport)
+
+	// files of local storage are downloaded by signed url without authorization
+	exports.GET("/files/*key", a.downloadExportFile)
+}
+
+type createExportInp struct {
+	Format  string          `json:"format"`
+	Options json.RawMessage `json:"options"`
+}
+
+func (a *App) createExport(ctx *gin.Context) {
+	var input createExportInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !models.IsValidExportFormat(input.Format) {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("format must be one of pdf, csv, anki, json").Error())
+		return
+	}
+
+	// only pdf has options, they are checked now so job doesn't fail later
+	var options json.RawMessage
+	if input.Format == models.ExportFormatPdf {
+		pdfOptions := pdfgenerator.Options{}
+		if len(input.Options) > 0 && string(input.Options) != "null" {
+			err = json.Unmarshal(input.Options, &pdfOptions)
+			if err != nil {
+				newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		pdfOptions, err = pdfOptions.Normalize()
+		if err != nil {
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		options, err = json.Marshal(pdfOptions)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	user := a.getContextUser(ctx)
+	collection := getContextCollection(ctx)
+
+	job, err := a.exportJobRepo.Create(models.ExportJob{
+		CollectionId: collection.Id,
+		OwnerId:      collection.OwnerId,
+		UserId:       user.Id,
+		Format:       input.Format,
+		Options:      options,
+		Status:       models.ExportStatusPending,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	notifyJobRunner(a.exportJobsQueue)
+
+	ctx.JSON(http.StatusAccepted, map[string]interface{}{
+		"message": "success",
+		"export":  job,
+	})
+}
+
+type getExportsResponse struct {
+	Exports []models.ExportJob `json:"exports"`
+}
+
+func (a *App) getExports(ctx *gin.Context) {
+	user := a.getContextUser(ctx)
+
+	jobs, err := a.exportJobRepo.GetByUserId(user.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getExportsResponse{
+		Exports: jobs,
+	})
+}
+
+type getExportResponse struct {
+	Export       models.ExportJob `json:"export"`
+	Url          string           `json:"url,omitempty"`
+	UrlExpiresAt *time.Time       `json:"urlExpiresAt,omitempty"`
+}
+
+// getExport returns status of the export, completed export has download url
+func (a *App) getExport(ctx *gin.Context) {
+	id := ctx.GetUint64("id")
+	if id == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	job, err := a.exportJobRepo.GetById(id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if job == nil || job.UserId != user.Id {
+		newErrorResponse(ctx, http.StatusNotFound, errExportNotFound.Error())
+		return
+	}
+
+	// user can lose access to shared collection after export is created
+	_, _, err = a.authorizeCollection(job.CollectionId, user.Id, collectionRead)
+	if err != nil {
+		collectionAccessErrorResponse(ctx, err)
+		return
+	}
+
+	response := getExportResponse{
+		Export: *job,
+	}
+
+	if job.Status == models.ExportStatusCompleted {
+		ttl := config.Config.Storage.UrlTTL()
+
+		url, err := a.storage.URL(job.FileKey, ttl)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		urlExpiresAt := time.Now().Add(ttl)
+		response.Url = url
+		response.UrlExpiresAt = &urlExpiresAt
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (a *App) downloadExportFile(ctx *gin.Context) {
+	localStorage, ok := a.storage.(*storage.LocalStorage)
+	if !ok {
+		newErrorResponse(ctx, http.StatusNotFound, errExportNotFound.Error())
+		return
+	}
+
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+
+	err := localStorage.Verify(key, ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		newErrorResponse(ctx, http.StatusForbidden, err.Error())
+		return
+	}
+
+	filePath, err := localStorage.Path(key)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusNotFound, errExportNotFound.Error())
+		return
+	}
+
+	ctx.FileAttachment(filePath, key[strings.LastIndex(key, "/")+1:])
+}
+
+// RunExportJobs generates files of pending exports, returns stale jobs to the queue
+// and removes expired files, it blocks forever
+func (a *App) RunExportJobs(interval time.Duration) {
+	jobRunner[models.ExportJob]{
+		name:  "exports",
+		queue: a.exportJobsQueue,
+		drain: true,
+		resetStale: func(now time.Time) error {
+			return a.exportJobRepo.ResetStale(now.Add(-exportStaleTimeout), maxExportAttempts, now.Add(exportRetention))
+		},
+		take: func(now time.Time) ([]models.ExportJob, error) {
+			return takeOne(a.exportJobRepo.TakePending(now))
+		},
+		run:     a.runExportJob,
+		cleanup: a.removeExpiredExports,
+	}.Run(interval)
+}
+
+func (a *App) runExportJob(job *models.ExportJob) {
+	err := a.generateExport(job)
+	now := time.Now()
+
+	if err != nil {
+		fmt.Printf("failed to export collection %d: %s\n", job.CollectionId, err.Error())
+
+		nextAttemptAt := retryAt(now, exportRetryDelay, job.Attempts)
+
+		job.Error = err.Error()
+		job.Status = models.ExportStatusPending
+		job.NextAttemptAt = &nextAttemptAt
+		if job.Attempts >= maxExportAttempts {
+			expiresAt := now.Add(exportRetention)
+
+			job.Status = models.ExportStatusFailed
+			job.FinishedAt = &now
+			job.ExpiresAt = &expiresAt
+		}
+	} else {
+		expiresAt := now.Add(exportRetention)
+
+		job.Status = models.ExportStatusCompleted
+		job.Error = ""
+		job.NextAttemptAt = nil
+		job.FinishedAt = &now
+		job.ExpiresAt = &expiresAt
+	}
+
+	err = a.exportJobRepo.Update(*job)
+	if err != nil {
+		fmt.Printf("failed to update export %d: %s\n", job.Id, err.Error())
+	}
+}
+
+// generateExport creates file of the job and stores it, job gets file key, name and size
+func (a *App) generateExport(job *models.ExportJob) error {
+	collection, err := a.collectionRepo.GetById(job.CollectionId)
+	if err != nil {
+		return err
+	}
+
+	if collection == nil {
+		return errCollectionNotFound
+	}
+
+	words, err := a.wordRepo.GetAllWords(elastic.CollectionWordsOperationCtx{UserId: collection.OwnerId, CollectionId: collection.Id})
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	var extension, contentType string
+
+	switch job.Format {
+	case models.ExportFormatPdf:
+		options := pdfgenerator.Options{}
+		if len(job.Options) > 0 {
+			err = json.Unmarshal(job.Options, &options)
+			if err != nil {
+				return err
+			}
+		}
+
+		data, err = pdfgenerator.GenerateCollectionPdf(words, collection.Name, options)
+		extension, contentType = "pdf", "application/pdf"
+	case models.ExportFormatCsv:
+		data, err = wordimport.Export(words, wordimport.FormatCSV)
+		extension, contentType = "csv", "text/csv; charset=utf-8"
+	case models.ExportFormatAnki:
+		cards := []anki.Card{}
+		for _, w := range words {
+			cards = append(cards, anki.Card{Id: w.Id, Front: w.Word, Back: joinTranslations(w), Tags: w.Tags})
+		}
+
+		data, err = anki.Export(collection.Name, cards)
+		extension, contentType = "apkg", "application/octet-stream"
+	case models.ExportFormatJson:
+		data, err = json.Marshal(map[string]interface{}{
+			"collection": collection,
+			"words":      words,
+			"exportedAt": time.Now(),
+		})
+		extension, contentType = "json", "application/json"
+	default:
+		return fmt.Errorf("unknown export format %s", job.Format)
+	}
+	if err != nil {
+		return err
+	}
+
+	job.FileName = fmt.Sprintf("%s.%s", exportFileName(collection.Name), extension)
+	job.FileKey = fmt.Sprintf("exports/%d/%d/%s", job.UserId, job.Id, job.FileName)
+	job.FileSize = uint64(len(data))
+
+	return a.storage.Store(job.FileKey, data, contentType)
+}
+
+func (a *App) removeExpiredExports(now time.Time) error {
+	jobs, err := a.exportJobRepo.GetExpired(now)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if job.FileKey != "" {
+			err = a.storage.Delete(job.FileKey)
+			if err != nil {
+				fmt.Printf("failed to delete file of export %d: %s\n", job.Id, err.Error())
+				continue
+			}
+		}
+
+		err = a.exportJobRepo.DeleteById(job.Id)
+		if err != nil {
+			fmt.Printf("failed to delete export %d: %s\n", job.Id, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// exportFileName keeps letters and digits of collection name, so it can be used in storage key
+func exportFileName(name string) string {
+	fileName := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(name))
+
+	if strings.Trim(fileName, "_") == "" {
+		return "collection"
+	}
+
+	return fileName
+}
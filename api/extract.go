@@ -0,0 +1,170 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"vacabulary/pkg/extractor"
+	"vacabulary/repositories/elastic"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxExtractTextSize limits pasted text and uploaded subtitles, 2mb
+	maxExtractTextSize = 2 << 20
+
+	defaultExtractLimit = 100
+	maxExtractLimit     = 1000
+
+	// extractCheckChunk is count of ranked candidates checked at once,
+	// the rest isn't checked when limit of new words is reached
+	extractCheckChunk = 200
+)
+
+func (a *App) InjectExtract(gr *gin.Engine) {
+	words := gr.Group("/word", a.authorizeRequest)
+
+	words.POST("/extract/collection/:collectionId", a.idParam("collectionId"), a.collectionAccess("collectionId", collectionEdit), a.extractWords)
+}
+
+// extractWordsResponse has Total count of words in the text, candidates are checked in rank order
+// only till limit of new words is reached, so Known is count of known words among Checked ones.
+// HasMore is set when new words are left after the limit.
+type extractWordsResponse struct {
+	Message    string                `json:"message"`
+	Format     string                `json:"format"`
+	Total      int                   `json:"total"`
+	Checked    int                   `json:"checked"`
+	Known      int                   `json:"known"`
+	HasMore    bool                  `json:"hasMore"`
+	Candidates []extractor.Candidate `json:"candidates"`
+}
+
+// extractWords returns new words of pasted text or subtitles ranked by frequency.
+// Form values: text or file, format (text, srt, vtt, by file extension when empty), limit.
+// Words which the user already has in own collections or in the target collection are skipped,
+// candidates can be added with sentences by /word/bulk.
+func (a *App) extractWords(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxExtractTextSize+1<<20)
+
+	content := ctx.PostForm("text")
+	format := strings.ToLower(ctx.PostForm("format"))
+
+	if content == "" {
+		fileHeader, err := ctx.FormFile("file")
+		if err != nil {
+			newErrorResponse(ctx, http.StatusBadRequest, errors.New("text or file is required").Error())
+			return
+		}
+
+		data, ok := readFormFile(ctx, maxExtractTextSize)
+		if !ok {
+			return
+		}
+
+		content = string(data)
+		if format == "" {
+			format = extractor.FormatByFileName(fileHeader.Filename)
+		}
+	}
+
+	if len(content) > maxExtractTextSize {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("text is too large").Error())
+		return
+	}
+
+	if format == "" {
+		format = extractor.FormatText
+	}
+
+	limit := defaultExtractLimit
+	if limitStr := ctx.PostForm("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > maxExtractLimit {
+			newErrorResponse(ctx, http.StatusBadRequest, fmt.Errorf("limit must be from 1 to %d", maxExtractLimit).Error())
+			return
+		}
+	}
+
+	text, err := extractor.PlainText(content, format)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collection := getContextCollection(ctx)
+	candidates := extractor.Extract(text, extractor.StopWords(collection.LangFrom))
+
+	user := a.getContextUser(ctx)
+
+	// own collections are in the user index, shared collection is in the index of its owner
+	wordsCtxs := []elastic.CollectionWordsOperationCtx{{UserId: user.Id}}
+	if collection.OwnerId != user.Id {
+		wordsCtxs = append(wordsCtxs, contextWordsCtx(ctx))
+	}
+
+	response := extractWordsResponse{
+		Message:    "success",
+		Format:     format,
+		Total:      len(candidates),
+		Candidates: []extractor.Candidate{},
+	}
+
+	// candidates are checked by chunks in rank order until new word after the limit is found
+	for from := 0; from < len(candidates) && !response.HasMore; from += extractCheckChunk {
+		to := from + extractCheckChunk
+		if to > len(candidates) {
+			to = len(candidates)
+		}
+
+		known, err := a.knownWords(candidates[from:to], wordsCtxs)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		for i, c := range candidates[from:to] {
+			if known[c.Word] {
+				response.Checked++
+				response.Known++
+				continue
+			}
+
+			if len(response.Candidates) == limit {
+				response.HasMore = true
+				break
+			}
+
+			response.Checked++
+			response.Candidates = append(response.Candidates, candidates[from+i])
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// knownWords returns lower case candidates which already are in any of given indexes
+func (a *App) knownWords(candidates []extractor.Candidate, wordsCtxs []elastic.CollectionWordsOperationCtx) (map[string]bool, error) {
+	values := []string{}
+	for _, c := range candidates {
+		values = append(values, c.Word)
+	}
+
+	known := map[string]bool{}
+	for _, wordsCtx := range wordsCtxs {
+		words, err := a.wordRepo.GetByWords(values, wordsCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, w := range words {
+			known[strings.ToLower(strings.TrimSpace(w.Word))] = true
+		}
+	}
+
+	return known, nil
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"vacabulary/models"
+	"vacabulary/pkg/wordhistory"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errHistoryNotFound = errors.New("word history not found")
+)
+
+func (a *App) InjectWordHistory(gr *gin.Engine) {
+	words := gr.Group("/word", a.authorizeRequest)
+
+	words.GET(":id/collection/:collectionId/history", a.idParam("collectionId"), a.collectionAccess("collectionId", collectionRead), a.getWordHistory)
+	words.POST(":id/collection/:collectionId/history/:historyId/revert", a.idParam("collectionId"), a.collectionAccess("collectionId", collectionEdit), a.idParam("historyId"), a.revertWord)
+}
+
+type getWordHistoryResponse struct {
+	Word    models.Word          `json:"word"`
+	History []models.WordHistory `json:"history"`
+}
+
+func (a *App) getWordHistory(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	word, ok := a.getCollectionWord(ctx, id)
+	if !ok {
+		return
+	}
+
+	history, err := a.wordHistoryRepo.GetByWordId(word.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getWordHistoryResponse{
+		Word:    *word,
+		History: history,
+	})
+}
+
+// revertWord returns the word to the version it had before the change from history
+func (a *App) revertWord(ctx *gin.Context) {
+	historyId := ctx.GetUint64("historyId")
+	if historyId == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get history id").Error())
+		return
+	}
+
+	id := ctx.Param("id")
+	if id == "" {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	word, ok := a.getCollectionWord(ctx, id)
+	if !ok {
+		return
+	}
+
+	history, err := a.wordHistoryRepo.GetById(historyId)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if history == nil || history.WordId != word.Id {
+		newErrorResponse(ctx, http.StatusNotFound, errHistoryNotFound.Error())
+		return
+	}
+
+	wordsCtx := contextWordsCtx(ctx)
+
+	revertedWord := wordhistory.Apply(*word, history.Snapshot)
+
+	// reverted word text can be already used by another word of collection
+	if revertedWord.Word != word.Word {
+		sameWord, err := a.wordRepo.Get(revertedWord.Word, wordsCtx)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if sameWord != nil {
+			newErrorResponse(ctx, http.StatusBadRequest, errors.New("such word already esists").Error())
+			return
+		}
+	}
+
+	err = a.wordRepo.Update(revertedWord, wordsCtx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+	a.recordWordHistory(*word, revertedWord, wordsCtx.UserId, user.Id, models.WordHistoryActionRevert, &history.Id)
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success",
+		"word":    revertedWord,
+	})
+}
+
+// recordWordHistory saves change of the word, nothing is saved when fields are not changed.
+// Word is already updated, so failure is only logged.
+func (a *App) recordWordHistory(before, after models.Word, ownerId uint64, actorId uint64, action string, revertedFrom *uint64) {
+	changes := wordhistory.Diff(before, after)
+	if len(changes) == 0 {
+		return
+	}
+
+	_, err := a.wordHistoryRepo.Create(models.WordHistory{
+		WordId:       before.Id,
+		CollectionId: before.CollectionId,
+		OwnerId:      ownerId,
+		ActorId:      actorId,
+		Action:       action,
+		RevertedFrom: revertedFrom,
+		Changes:      changes,
+		Snapshot:     wordhistory.Snapshot(before),
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		fmt.Printf("failed to save history of word %s: %s\n", before.Id, err.Error())
+	}
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"vacabulary/models"
+	"vacabulary/pkg/wordimport"
+	"vacabulary/repositories/elastic"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxImportFileSize limits uploaded csv/tsv file, 5mb
+	maxImportFileSize = 5 << 20
+
+	importRowStatusValid     = "valid"
+	importRowStatusInvalid   = "invalid"
+	importRowStatusDuplicate = "duplicate"
+)
+
+func (a *App) InjectImport(gr *gin.Engine) {
+	words := gr.Group("/word", a.authorizeRequest)
+
+	words.POST("/import/collection/:collectionId", a.idParam("collectionId"), a.collectionAccess("collectionId", collectionEdit), a.importWords)
+}
+
+type importRow struct {
+	Line   int         `json:"line"`
+	Status string      `json:"status"`
+	Word   models.Word `json:"word"`
+	Errors []string    `json:"errors"`
+}
+
+type importWordsResponse struct {
+	Message    string      `json:"message"`
+	DryRun     bool        `json:"dryRun"`
+	Total      int         `json:"total"`
+	Valid      int         `json:"valid"`
+	Invalid    int         `json:"invalid"`
+	Duplicates int         `json:"duplicates"`
+	Imported   int         `json:"imported"`
+	Rows       []importRow `json:"rows"`
+}
+
+// importWords imports words from multipart csv/tsv file.
+// Form values: file, format (csv, tsv, by file extension when empty), header (true by default),
+// word, translation, partOfSpeech, sentence columns (number from 1 or header name), dryRun.
+// Invalid rows and duplicates are never imported, dry run only returns the preview.
+func (a *App) importWords(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportFileSize+1<<20)
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get file").Error())
+		return
+	}
+
+	if fileHeader.Size > maxImportFileSize {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("file is too large").Error())
+		return
+	}
+
+	format := strings.ToLower(ctx.PostForm("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+	}
+
+	if _, err := wordimport.Delimiter(format); err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	hasHeader, err := formBool(ctx, "header", true)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dryRun, err := formBool(ctx, "dryRun", false)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mapping := wordimport.Mapping{
+		Word:         ctx.DefaultPostForm("word", "1"),
+		Translation:  ctx.DefaultPostForm("translation", "2"),
+		PartOfSpeech: ctx.PostForm("partOfSpeech"),
+		Sentence:     ctx.PostForm("sentence"),
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer file.Close()
+
+	parsedRows, err := wordimport.Parse(file, format, hasHeader, mapping)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collection := getContextCollection(ctx)
+	wordsCtx := contextWordsCtx(ctx)
+
+	rows, err := a.checkImportRows(parsedRows, collection.Id, wordsCtx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := importWordsResponse{
+		Message: "success",
+		DryRun:  dryRun,
+		Total:   len(rows),
+		Rows:    rows,
+	}
+
+	words := []models.Word{}
+	for _, r := range rows {
+		switch r.Status {
+		case importRowStatusValid:
+			response.Valid++
+			words = append(words, r.Word)
+		case importRowStatusInvalid:
+			response.Invalid++
+		case importRowStatusDuplicate:
+			response.Duplicates++
+		}
+	}
+
+	if !dryRun && len(words) > 0 {
+		err = a.wordRepo.BulkCreate(words, wordsCtx)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+		response.Imported = len(words)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// checkImportRows marks rows with errors as invalid and words which already are
+// in the collection or earlier in the file as duplicates
+func (a *App) checkImportRows(parsedRows []wordimport.Row, collectionId uint64, wordsCtx elastic.CollectionWordsOperationCtx) ([]importRow, error) {
+	values := []string{}
+	for _, r := range parsedRows {
+		if r.IsValid() {
+			values = append(values, r.Word.Word)
+		}
+	}
+
+	// repository looks up words by chunks itself
+	words, err := a.wordRepo.GetByWords(values, wordsCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]bool{}
+	for _, w := range words {
+		existing[strings.ToLower(w.Word)] = true
+	}
+
+	rows := []importRow{}
+	firstLines := map[string]int{}
+	for _, r := range parsedRows {
+		row := importRow{
+			Line:   r.Line,
+			Status: importRowStatusValid,
+			Word:   r.Word,
+			Errors: r.Errors,
+		}
+		row.Word.CollectionId = collectionId
+
+		key := strings.ToLower(r.Word.Word)
+		switch {
+		case !r.IsValid():
+			row.Status = importRowStatusInvalid
+		case existing[key]:
+			row.Status = importRowStatusDuplicate
+			row.Errors = append(row.Errors, "word already exists in collection")
+		case firstLines[key] != 0:
+			row.Status = importRowStatusDuplicate
+			row.Errors = append(row.Errors, fmt.Sprintf("word is already on line %d", firstLines[key]))
+		default:
+			firstLines[key] = r.Line
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// formBool returns bool form value, def is used for empty value
+func formBool(ctx *gin.Context, key string, def bool) (bool, error) {
+	value := ctx.PostForm(key)
+	if value == "" {
+		return def, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", key)
+	}
+
+	return result, nil
+}
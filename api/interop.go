@@ -0,0 +1,363 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"vacabulary/config"
+	el "vacabulary/db/elastic"
+	"vacabulary/models"
+	"vacabulary/pkg/anki"
+	"vacabulary/pkg/quizlet"
+	"vacabulary/pkg/wordimport"
+	"vacabulary/repositories/elastic"
+	"vacabulary/repositories/postgres"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	importSourceAnki    = "anki"
+	importSourceQuizlet = "quizlet"
+
+	// maxAnkiFileSize limits uploaded anki package, 50mb
+	maxAnkiFileSize = 50 << 20
+)
+
+// InjectInterop adds export and import of collections in formats of Anki and Quizlet
+func (a *App) InjectInterop(gr *gin.Engine) {
+	collections := gr.Group("/collection", a.authorizeRequest)
+
+	collections.GET(":id/export/anki", a.idParam("id"), a.collectionAccess("id", collectionRead), a.exportAnki)
+	collections.GET(":id/export/quizlet", a.idParam("id"), a.collectionAccess("id", collectionRead), a.exportQuizlet)
+
+	collections.POST("/import/anki", a.importAnki)
+	collections.POST("/import/quizlet", a.importQuizlet)
+}
+
+func (a *App) exportAnki(ctx *gin.Context) {
+	collection := getContextCollection(ctx)
+
+	words, err := a.wordRepo.GetAllWords(contextWordsCtx(ctx))
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	cards := []anki.Card{}
+	for _, w := range words {
+		cards = append(cards, anki.Card{
+			Id:    w.Id,
+			Front: w.Word,
+			Back:  joinTranslations(w),
+			Tags:  w.Tags,
+		})
+	}
+
+	file, err := anki.Export(collection.Name, cards)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Writer.Header().Add("Content-Disposition", attachmentDisposition(collection.Name+".apkg"))
+	ctx.Data(http.StatusOK, "application/octet-stream", file)
+}
+
+func (a *App) exportQuizlet(ctx *gin.Context) {
+	collection := getContextCollection(ctx)
+
+	words, err := a.wordRepo.GetAllWords(contextWordsCtx(ctx))
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	cards := []quizlet.Card{}
+	for _, w := range words {
+		cards = append(cards, quizlet.Card{
+			Term:       w.Word,
+			Definition: joinTranslations(w),
+		})
+	}
+
+	ctx.Writer.Header().Add("Content-Disposition", attachmentDisposition(collection.Name+".txt"))
+	ctx.Data(http.StatusOK, "text/tab-separated-values; charset=utf-8", quizlet.Export(cards))
+}
+
+// attachmentDisposition returns Content-Disposition of downloaded file, names
+// with spaces, quotes and not ascii characters are quoted or encoded
+func attachmentDisposition(fileName string) string {
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": fileName})
+	if disposition == "" {
+		return "attachment"
+	}
+
+	return disposition
+}
+
+// joinTranslations returns all translations of the word in one field, main translation is the first
+func joinTranslations(w models.Word) string {
+	translations := []string{}
+	seen := map[string]bool{}
+	for _, t := range append([]string{w.Translation}, w.Translations...) {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		translations = append(translations, t)
+	}
+
+	return strings.Join(translations, ", ")
+}
+
+type importCollectionResponse struct {
+	Message    string             `json:"message"`
+	Collection *models.Collection `json:"collection"`
+	Total      int                `json:"total"`
+	Imported   int                `json:"imported"`
+	Invalid    int                `json:"invalid"`
+	Duplicates int                `json:"duplicates"`
+	Skipped    []importRow        `json:"skipped"`
+}
+
+// importAnki imports notes of .apkg package, front is the word and back is the translation
+func (a *App) importAnki(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxAnkiFileSize+1<<20)
+
+	data, ok := readFormFile(ctx, maxAnkiFileSize)
+	if !ok {
+		return
+	}
+
+	cards, err := anki.Import(data)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows := []wordimport.Row{}
+	for i, c := range cards {
+		rows = append(rows, wordimport.NewRow(i+1, models.Word{
+			Word:        c.Front,
+			Translation: c.Back,
+			Tags:        c.Tags,
+		}))
+	}
+
+	a.importCollectionRows(ctx, rows, importSourceAnki)
+}
+
+// importQuizlet imports set exported from quizlet with tab between term and definition
+func (a *App) importQuizlet(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportFileSize+1<<20)
+
+	data, ok := readFormFile(ctx, maxImportFileSize)
+	if !ok {
+		return
+	}
+
+	rows, err := quizlet.Import(bytes.NewReader(data))
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	a.importCollectionRows(ctx, rows, importSourceQuizlet)
+}
+
+// importCollectionRows saves imported words into collection of the request.
+// Invalid words and words which already are in the collection are skipped.
+func (a *App) importCollectionRows(ctx *gin.Context, parsedRows []wordimport.Row, source string) {
+	collection, created, ok := a.getImportCollection(ctx, source)
+	if !ok {
+		return
+	}
+
+	response, err := a.saveImportRows(parsedRows, collection)
+	if err != nil {
+		if created {
+			a.rollbackImportCollection(collection)
+		}
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// getImportCollection returns existing collection passed by collectionId form value
+// or new collection created with name, langFrom and langTo form values,
+// created is true for new collection, it is removed when import fails
+func (a *App) getImportCollection(ctx *gin.Context, source string) (collection *models.Collection, created bool, ok bool) {
+	collectionIdStr := ctx.PostForm("collectionId")
+	if collectionIdStr == "" {
+		collection, ok = a.createImportCollection(ctx, source)
+		return collection, ok, ok
+	}
+
+	collectionId, err := strconv.ParseUint(collectionIdStr, 10, 64)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("collectionId not valid").Error())
+		return nil, false, false
+	}
+
+	user := a.getContextUser(ctx)
+
+	collection, _, err = a.authorizeCollection(collectionId, user.Id, collectionEdit)
+	if err != nil {
+		collectionAccessErrorResponse(ctx, err)
+		return nil, false, false
+	}
+
+	return collection, false, true
+}
+
+// rollbackImportCollection removes collection created for failed import or clone with words saved before failure,
+// failure is only logged because error of the import is returned
+func (a *App) rollbackImportCollection(collection *models.Collection) {
+	deletion, err := a.deleteCollectionCascade(*collection)
+	if err != nil {
+		fmt.Printf("failed to remove collection %d of failed import: %s\n", collection.Id, err.Error())
+		return
+	}
+
+	if deletion.Status == models.DeletionStatusFailed {
+		fmt.Printf("failed to remove words of collection %d of failed import: %s\n", collection.Id, deletion.Error)
+	}
+}
+
+// saveImportRows creates valid words of the rows which are not in the collection yet
+func (a *App) saveImportRows(parsedRows []wordimport.Row, collection *models.Collection) (*importCollectionResponse, error) {
+	wordsCtx := elastic.CollectionWordsOperationCtx{UserId: collection.OwnerId, CollectionId: collection.Id}
+
+	rows, err := a.checkImportRows(parsedRows, collection.Id, wordsCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &importCollectionResponse{
+		Message:    "success",
+		Collection: collection,
+		Total:      len(rows),
+		Skipped:    []importRow{},
+	}
+
+	words := []models.Word{}
+	for _, r := range rows {
+		switch r.Status {
+		case importRowStatusValid:
+			words = append(words, r.Word)
+			continue
+		case importRowStatusInvalid:
+			response.Invalid++
+		case importRowStatusDuplicate:
+			response.Duplicates++
+		}
+		response.Skipped = append(response.Skipped, r)
+	}
+
+	if len(words) > 0 {
+		err = a.wordRepo.BulkCreate(words, wordsCtx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	response.Imported = len(words)
+
+	return response, nil
+}
+
+// createImportCollection creates collection for imported words,
+// name is generated from source and date when it is empty
+func (a *App) createImportCollection(ctx *gin.Context, source string) (*models.Collection, bool) {
+	name := strings.TrimSpace(ctx.PostForm("name"))
+	if name == "" {
+		name = fmt.Sprintf("%s import %s", source, time.Now().Format("2006-01-02 15:04"))
+	}
+
+	langFrom := ctx.PostForm("langFrom")
+	langTo := ctx.PostForm("langTo")
+
+	// check languages
+	if langFrom == "" || langTo == "" {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("lang from and lang to can't be empty").Error())
+		return nil, false
+	}
+
+	if langFrom == langTo {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("lang from and lang to can't be same").Error())
+		return nil, false
+	}
+
+	schedulerSettings, err := normalizeSchedulerSettings(models.SchedulerSettings{})
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	user := a.getContextUser(ctx)
+
+	collection, err := a.collectionRepo.Create(models.Collection{
+		Name:              name,
+		OwnerId:           user.Id,
+		LangFrom:          langFrom,
+		LangTo:            langTo,
+		CreatedAt:         time.Now(),
+		SchedulerSettings: schedulerSettings,
+	})
+	if err != nil {
+		if errors.Is(err, postgres.ErrCollectionNameExists) {
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			return nil, false
+		}
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	elClient := el.NewElasticClient(config.Config.Elastic)
+	err = elClient.CreateCollectionAliases(user.Id, collection.Id)
+	if err != nil {
+		a.rollbackImportCollection(collection)
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	return collection, true
+}
+
+// readFormFile returns content of multipart file from "file" form value
+func readFormFile(ctx *gin.Context, maxSize int64) ([]byte, bool) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get file").Error())
+		return nil, false
+	}
+
+	if fileHeader.Size > maxSize {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("file is too large").Error())
+		return nil, false
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	return data, true
+}
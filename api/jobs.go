@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+// jobRunner runs background jobs stored in postgres every tick, queue wakes it up before the next tick.
+// Jobs returned by take are run one by one, take is called again in the same tick while it returns jobs
+// when drain is set, so jobs queued during the tick don't wait for the next one.
+// resetStale and cleanup are optional.
+type jobRunner[J any] struct {
+	name       string
+	queue      <-chan struct{}
+	drain      bool
+	resetStale func(now time.Time) error
+	take       func(now time.Time) ([]J, error)
+	run        func(job *J)
+	cleanup    func(now time.Time) error
+}
+
+// Run blocks forever
+func (r jobRunner[J]) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// nil queue never wakes up the runner
+		select {
+		case <-ticker.C:
+		case <-r.queue:
+		}
+
+		r.tick()
+	}
+}
+
+func (r jobRunner[J]) tick() {
+	if r.resetStale != nil {
+		err := r.resetStale(time.Now())
+		if err != nil {
+			fmt.Printf("failed to reset stale %s: %s\n", r.name, err.Error())
+		}
+	}
+
+	for {
+		jobs, err := r.take(time.Now())
+		if err != nil {
+			fmt.Printf("failed to get %s: %s\n", r.name, err.Error())
+			break
+		}
+
+		for i := range jobs {
+			r.run(&jobs[i])
+		}
+
+		if !r.drain || len(jobs) == 0 {
+			break
+		}
+	}
+
+	if r.cleanup != nil {
+		err := r.cleanup(time.Now())
+		if err != nil {
+			fmt.Printf("failed to clean up %s: %s\n", r.name, err.Error())
+		}
+	}
+}
+
+// takeOne converts result of repository which takes one job to the result of take
+func takeOne[J any](job *J, err error) ([]J, error) {
+	if err != nil || job == nil {
+		return nil, err
+	}
+
+	return []J{*job}, nil
+}
+
+// notifyJobRunner wakes up runner of the queue without waiting for the next tick
+func notifyJobRunner(queue chan struct{}) {
+	select {
+	case queue <- struct{}{}:
+	default:
+	}
+}
+
+// retryAt returns time of the next attempt of failed job, delay is doubled after every attempt
+func retryAt(now time.Time, delay time.Duration, attempts uint64) time.Time {
+	if attempts == 0 {
+		return now.Add(delay)
+	}
+
+	return now.Add(delay << (attempts - 1))
+}
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"vacabulary/models"
+	"vacabulary/pkg/kindle"
+	"vacabulary/pkg/wordimport"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	importSourceKindle = "kindle"
+
+	// maxKindleFileSize limits uploaded vocab.db, 50mb
+	maxKindleFileSize = 50 << 20
+
+	// maxKindleSentences limits usages saved as sentences of one word
+	maxKindleSentences = 5
+
+	// maxKindleTranslations limits words translated by one import
+	maxKindleTranslations = 200
+)
+
+// InjectKindle adds import of words looked up in kindle vocabulary builder
+func (a *App) InjectKindle(gr *gin.Engine) {
+	kindleGroup := gr.Group("/kindle", a.authorizeRequest)
+
+	kindleGroup.POST("/vocabulary", a.getKindleVocabulary)
+	kindleGroup.POST("/import", a.importKindle)
+}
+
+// getKindleVocabulary returns books and looked up words of uploaded vocab.db,
+// book form value returns only words looked up in that book
+func (a *App) getKindleVocabulary(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxKindleFileSize+1<<20)
+
+	vocabulary, ok := readKindleVocabulary(ctx)
+	if !ok {
+		return
+	}
+
+	if bookId := ctx.PostForm("book"); bookId != "" {
+		vocabulary.Words = filterKindleWords(vocabulary.Words, nil, map[string]bool{bookId: true})
+	}
+
+	ctx.JSON(http.StatusOK, vocabulary)
+}
+
+type importKindleResponse struct {
+	importCollectionResponse
+	TranslationJob *models.TranslationJob `json:"translationJob"`
+}
+
+// importKindle imports words selected by comma separated words (word ids) and books (book ids) form values.
+// Usages become sentences of the word, stem=false keeps word form instead of dictionary form,
+// translate=true queues translation of new words with languages of the collection, its status
+// is returned by translation job.
+func (a *App) importKindle(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxKindleFileSize+1<<20)
+
+	vocabulary, ok := readKindleVocabulary(ctx)
+	if !ok {
+		return
+	}
+
+	wordIds := formList(ctx, "words")
+	bookIds := formList(ctx, "books")
+	if len(wordIds) == 0 && len(bookIds) == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("select words or books to import").Error())
+		return
+	}
+
+	useStem, err := formBool(ctx, "stem", true)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	translate, err := formBool(ctx, "translate", false)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows := kindleRows(filterKindleWords(vocabulary.Words, wordIds, bookIds), useStem)
+	if len(rows) == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("no words selected").Error())
+		return
+	}
+
+	if len(rows) > wordimport.MaxRows {
+		newErrorResponse(ctx, http.StatusBadRequest, fmt.Errorf("can not import more than %d words", wordimport.MaxRows).Error())
+		return
+	}
+
+	// checked before collection is created, so failed request doesn't leave empty collection
+	if translate && len(rows) > maxKindleTranslations {
+		newErrorResponse(ctx, http.StatusBadRequest, fmt.Errorf("can not translate more than %d words, select less words or import without translation", maxKindleTranslations).Error())
+		return
+	}
+
+	collection, created, ok := a.getImportCollection(ctx, importSourceKindle)
+	if !ok {
+		return
+	}
+
+	response, err := a.saveImportRows(rows, collection)
+	if err != nil {
+		if created {
+			a.rollbackImportCollection(collection)
+		}
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var translationJob *models.TranslationJob
+	if translate && response.Imported > 0 {
+		user := a.getContextUser(ctx)
+
+		translationJob, err = a.createTranslationJob(importedWords(rows, response.Skipped), collection, user.Id)
+		if err != nil {
+			if created {
+				a.rollbackImportCollection(collection)
+			}
+			newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, importKindleResponse{
+		importCollectionResponse: *response,
+		TranslationJob:           translationJob,
+	})
+}
+
+func readKindleVocabulary(ctx *gin.Context) (*kindle.Vocabulary, bool) {
+	data, ok := readFormFile(ctx, maxKindleFileSize)
+	if !ok {
+		return nil, false
+	}
+
+	vocabulary, err := kindle.Read(data)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	return vocabulary, true
+}
+
+// formList returns comma separated values of the form key as set
+func formList(ctx *gin.Context, key string) map[string]bool {
+	values := map[string]bool{}
+	for _, v := range ctx.PostFormArray(key) {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values[item] = true
+			}
+		}
+	}
+
+	return values
+}
+
+// filterKindleWords returns words with given ids and words looked up in given books,
+// usages of words selected by books are limited to these books
+func filterKindleWords(words []kindle.Word, wordIds, bookIds map[string]bool) []kindle.Word {
+	filtered := []kindle.Word{}
+	for _, w := range words {
+		if wordIds[w.Id] {
+			filtered = append(filtered, w)
+			continue
+		}
+
+		usages := []kindle.Usage{}
+		for _, u := range w.Usages {
+			if bookIds[u.BookId] {
+				usages = append(usages, u)
+			}
+		}
+
+		if len(usages) > 0 {
+			w.Usages = usages
+			filtered = append(filtered, w)
+		}
+	}
+
+	return filtered
+}
+
+// kindleRows converts kindle words to import rows, word forms with the same stem are merged
+func kindleRows(words []kindle.Word, useStem bool) []wordimport.Row {
+	rows := []wordimport.Row{}
+	index := map[string]int{}
+
+	for _, w := range words {
+		value := w.Word
+		if useStem && w.Stem != "" {
+			value = w.Stem
+		}
+
+		key := strings.ToLower(value)
+		i, ok := index[key]
+		if !ok {
+			row := wordimport.Row{
+				Line:   len(rows) + 1,
+				Word:   models.Word{Word: value, Sentences: []string{}},
+				Errors: []string{},
+			}
+			if value == "" {
+				row.Errors = append(row.Errors, "word is empty")
+			}
+
+			i = len(rows)
+			index[key] = i
+			rows = append(rows, row)
+		}
+
+		word := &rows[i].Word
+		for _, u := range w.Usages {
+			if u.Text == "" || len(word.Sentences) >= maxKindleSentences || containsString(word.Sentences, u.Text) {
+				continue
+			}
+			word.Sentences = append(word.Sentences, u.Text)
+		}
+
+		if len(word.Sentences) > 0 {
+			word.Scentance = word.Sentences[0]
+		}
+	}
+
+	return rows
+}
+
+// importedWords returns words of the rows which are not skipped by import
+func importedWords(rows []wordimport.Row, skipped []importRow) []string {
+	skippedLines := map[int]bool{}
+	for _, r := range skipped {
+		skippedLines[r.Line] = true
+	}
+
+	words := []string{}
+	for _, r := range rows {
+		if !skippedLines[r.Line] {
+			words = append(words, r.Word.Word)
+		}
+	}
+
+	return words
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,304 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"vacabulary/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errMemberNotFound = errors.New("collection member not found")
+)
+
+func (a *App) InjectMembers(gr *gin.Engine) {
+	collections := gr.Group("/collection", a.authorizeRequest)
+
+	collections.GET("/shared", a.getSharedCollections)
+	collections.GET(":id/members", a.idParam("id"), a.collectionAccess("id", collectionRead), a.getCollectionMembers)
+	collections.POST(":id/members", a.idParam("id"), a.collectionAccess("id", collectionManage), a.inviteCollectionMember)
+	collections.PUT(":id/members/:memberId", a.idParam("id"), a.idParam("memberId"), a.collectionAccess("id", collectionManage), a.updateCollectionMember)
+	collections.DELETE(":id/members/:memberId", a.idParam("id"), a.idParam("memberId"), a.collectionAccess("id", collectionRead), a.deleteCollectionMember)
+
+	invitations := gr.Group("/invitation", a.authorizeRequest)
+
+	invitations.GET("/all", a.getInvitations)
+	invitations.POST(":id/accept", a.idParam("id"), a.acceptInvitation)
+	invitations.POST(":id/decline", a.idParam("id"), a.declineInvitation)
+}
+
+type sharedCollectionResponse struct {
+	Collection *models.Collection    `json:"collection"`
+	Role       models.CollectionRole `json:"role"`
+}
+
+func (a *App) getSharedCollections(ctx *gin.Context) {
+	user := a.getContextUser(ctx)
+
+	memberships, err := a.collectionMemberRepo.GetByUserId(user.Id, models.MemberStatusAccepted)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	collections := []sharedCollectionResponse{}
+	for _, m := range memberships {
+		if m.Collection == nil {
+			continue
+		}
+
+		collections = append(collections, sharedCollectionResponse{
+			Collection: m.Collection,
+			Role:       m.Role,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"collections": collections,
+	})
+}
+
+func (a *App) getCollectionMembers(ctx *gin.Context) {
+	collection := getContextCollection(ctx)
+
+	members, err := a.collectionMemberRepo.GetByCollectionId(collection.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"members": members,
+	})
+}
+
+type inviteCollectionMemberInp struct {
+	Email string                `json:"email"`
+	Role  models.CollectionRole `json:"role"`
+}
+
+func (a *App) inviteCollectionMember(ctx *gin.Context) {
+	var input inviteCollectionMemberInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !input.Role.IsValidMemberRole() {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("role must be one of viewer, editor").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+	collection := getContextCollection(ctx)
+
+	invitedUser, err := a.userRepo.GetByEmail(strings.TrimSpace(input.Email))
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if invitedUser == nil {
+		newErrorResponse(ctx, http.StatusNotFound, errors.New("user with such email not founded").Error())
+		return
+	}
+
+	if invitedUser.Id == collection.OwnerId {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("owner can't be invited to own collection").Error())
+		return
+	}
+
+	member, err := a.collectionMemberRepo.GetByCollectionAndUser(collection.Id, invitedUser.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if member != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("user already invited to collection").Error())
+		return
+	}
+
+	member, err = a.collectionMemberRepo.Create(models.CollectionMember{
+		CollectionId: collection.Id,
+		UserId:       invitedUser.Id,
+		Role:         input.Role,
+		Status:       models.MemberStatusInvited,
+		InvitedBy:    user.Id,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success",
+		"member":  member,
+	})
+}
+
+type updateCollectionMemberInp struct {
+	Role models.CollectionRole `json:"role"`
+}
+
+func (a *App) updateCollectionMember(ctx *gin.Context) {
+	memberId := ctx.GetUint64("memberId")
+	if memberId == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	var input updateCollectionMemberInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !input.Role.IsValidMemberRole() {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("role must be one of viewer, editor").Error())
+		return
+	}
+
+	collection := getContextCollection(ctx)
+
+	member, err := a.collectionMemberRepo.GetById(memberId)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if member == nil || member.CollectionId != collection.Id {
+		newErrorResponse(ctx, http.StatusNotFound, errMemberNotFound.Error())
+		return
+	}
+
+	err = a.collectionMemberRepo.UpdateRole(memberId, input.Role)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success update",
+	})
+}
+
+// deleteCollectionMember removes member by owner or lets member leave collection
+func (a *App) deleteCollectionMember(ctx *gin.Context) {
+	memberId := ctx.GetUint64("memberId")
+	if memberId == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+	collection := getContextCollection(ctx)
+
+	member, err := a.collectionMemberRepo.GetById(memberId)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if member == nil || member.CollectionId != collection.Id {
+		newErrorResponse(ctx, http.StatusNotFound, errMemberNotFound.Error())
+		return
+	}
+
+	if member.UserId != user.Id {
+		err = collectionPolicy(getContextCollectionRole(ctx), collectionManage)
+		if err != nil {
+			collectionAccessErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	err = a.collectionMemberRepo.DeleteById(memberId)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success delete",
+	})
+}
+
+func (a *App) getInvitations(ctx *gin.Context) {
+	user := a.getContextUser(ctx)
+
+	invitations, err := a.collectionMemberRepo.GetByUserId(user.Id, models.MemberStatusInvited)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"invitations": invitations,
+	})
+}
+
+func (a *App) acceptInvitation(ctx *gin.Context) {
+	invitation, ok := a.getContextInvitation(ctx)
+	if !ok {
+		return
+	}
+
+	err := a.collectionMemberRepo.Accept(invitation.Id, time.Now())
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":    "success",
+		"collection": invitation.Collection,
+	})
+}
+
+func (a *App) declineInvitation(ctx *gin.Context) {
+	invitation, ok := a.getContextInvitation(ctx)
+	if !ok {
+		return
+	}
+
+	err := a.collectionMemberRepo.DeleteById(invitation.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success",
+	})
+}
+
+// getContextInvitation returns pending invitation of the user from id param
+func (a *App) getContextInvitation(ctx *gin.Context) (*models.CollectionMember, bool) {
+	id := ctx.GetUint64("id")
+	if id == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return nil, false
+	}
+
+	user := a.getContextUser(ctx)
+
+	invitation, err := a.collectionMemberRepo.GetById(id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	if invitation == nil || invitation.UserId != user.Id || invitation.Status != models.MemberStatusInvited {
+		newErrorResponse(ctx, http.StatusNotFound, errors.New("invitation not found").Error())
+		return nil, false
+	}
+
+	return invitation, true
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"vacabulary/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	defaultActivityDays = 365
+	// maxActivityDays limits days of the range including from and to days,
+	// every day of the range is a bucket of date histogram
+	maxActivityDays = 366
+)
+
+func (a *App) InjectPersonalStatistic(gr *gin.Engine) {
+	statistic := gr.Group("/me/statistic", a.authorizeRequest)
+
+	statistic.GET("/words/perTime", a.getMyCountOfWordsPerTime)
+	statistic.GET("/words/perCollection", a.getMyCountOfWordsPerCollection)
+	statistic.GET("/words/partsOfSpeech", a.getMyCountOfWordsPerPartOfSpeech)
+	statistic.GET("/activity", a.getMyActivity)
+}
+
+func (a *App) getMyCountOfWordsPerTime(ctx *gin.Context) {
+	perTime := ctx.DefaultQuery("time", "day")
+	if perTime != "day" && perTime != "week" && perTime != "month" {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("time must be one of day, week, month").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+	location := user.Settings.Location()
+
+	countOfWordsPerTime, err := a.wordRepo.GetCountOfWordsPerTime([]uint64{user.Id}, perTime, location.String())
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":   "success",
+		"statistic": countOfWordsPerTime,
+	})
+}
+
+func (a *App) getMyCountOfWordsPerCollection(ctx *gin.Context) {
+	user := a.getContextUser(ctx)
+
+	collections, err := a.collectionRepo.GetByOwnerId(user.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	countOfWords, err := a.wordRepo.GetCountOfWordsPerCollection(user.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	countByCollection := map[uint64]uint64{}
+	for _, c := range countOfWords {
+		countByCollection[c.CollectionId] = c.Count
+	}
+
+	// collections without words are returned too
+	statistic := []models.WordsPerCollection{}
+	for _, c := range collections {
+		statistic = append(statistic, models.WordsPerCollection{
+			CollectionId:   c.Id,
+			CollectionName: c.Name,
+			Count:          countByCollection[c.Id],
+		})
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":   "success",
+		"statistic": statistic,
+	})
+}
+
+func (a *App) getMyCountOfWordsPerPartOfSpeech(ctx *gin.Context) {
+	user := a.getContextUser(ctx)
+
+	countOfWords, err := a.wordRepo.GetCountOfWordsPerPartOfSpeech(user.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":   "success",
+		"statistic": countOfWords,
+	})
+}
+
+func (a *App) getMyActivity(ctx *gin.Context) {
+	user := a.getContextUser(ctx)
+	location := user.Settings.Location()
+
+	from, to, err := getDateRangeParams(ctx, location)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	activity, err := a.getUserActivity(user.Id, from, to)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":  "success",
+		"timezone": location.String(),
+		"activity": activity,
+	})
+}
+
+// getUserActivity returns words added and reviewed per day,
+// days are bucketed in the location of from and to dates
+func (a *App) getUserActivity(userId uint64, from, to time.Time) ([]models.ActivityPerDay, error) {
+	timezone := to.Location().String()
+
+	wordsAdded, err := a.wordRepo.GetCountOfWordsPerDay(userId, from, to, timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	wordsReviewed, err := a.studySessionRepo.GetAnswersCountPerDay(userId, from, to, timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeActivity(from, to, wordsAdded, wordsReviewed), nil
+}
+
+// mergeActivity builds heatmap with one entry for every day in range
+func mergeActivity(from, to time.Time, wordsAdded []models.WordsAddedPerTime, wordsReviewed []models.AnswersPerDay) []models.ActivityPerDay {
+	addedByDate := map[string]uint64{}
+	for _, w := range wordsAdded {
+		addedByDate[w.Date] = w.Count
+	}
+
+	reviewedByDate := map[string]uint64{}
+	for _, w := range wordsReviewed {
+		reviewedByDate[w.Date] = w.Count
+	}
+
+	activity := []models.ActivityPerDay{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dateLayout)
+
+		activity = append(activity, models.ActivityPerDay{
+			Date:          date,
+			WordsAdded:    addedByDate[date],
+			WordsReviewed: reviewedByDate[date],
+			Total:         addedByDate[date] + reviewedByDate[date],
+		})
+	}
+
+	return activity
+}
+
+// getDateRangeParams returns from and to days in the location, last year by default
+func getDateRangeParams(ctx *gin.Context, location *time.Location) (time.Time, time.Time, error) {
+	to := startOfDay(time.Now(), location)
+	if toStr := ctx.Query("to"); toStr != "" {
+		parsedTo, err := time.ParseInLocation(dateLayout, toStr, location)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to date must be in format YYYY-MM-DD")
+		}
+		to = parsedTo
+	}
+
+	from := to.AddDate(0, 0, -defaultActivityDays)
+	if fromStr := ctx.Query("from"); fromStr != "" {
+		parsedFrom, err := time.ParseInLocation(dateLayout, fromStr, location)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from date must be in format YYYY-MM-DD")
+		}
+		from = parsedFrom
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, errors.New("from date can't be after to date")
+	}
+
+	if from.Before(to.AddDate(0, 0, -(maxActivityDays - 1))) {
+		return time.Time{}, time.Time{}, fmt.Errorf("date range can't be longer than %d days", maxActivityDays)
+	}
+
+	return from, endOfDay(to), nil
+}
+
+func startOfDay(t time.Time, location *time.Location) time.Time {
+	t = t.In(location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+}
+
+func endOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, int(time.Second-time.Nanosecond), t.Location())
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"vacabulary/models"
+	"vacabulary/repositories/elastic"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errNoCollectionAccess       = errors.New("no access to collection")
+	errReadOnlyCollection       = errors.New("collection is read only for user")
+	errNotCollectionOwner       = errors.New("only owner can manage collection")
+	errNotCollectionOwnerReview = errors.New("review progress belongs to collection owner, only owner can review words")
+	errWordNotFound             = errors.New("word not found in collection")
+)
+
+// collectionAction is an operation user wants to do with collection
+type collectionAction int
+
+const (
+	// read collection and its words
+	collectionRead collectionAction = iota
+	// change words of collection
+	collectionEdit
+	// change or delete collection itself, manage members
+	collectionManage
+	// review words, progress is stored in words of the owner, so members can't change it
+	collectionReview
+)
+
+// collectionPolicy checks that role allows action
+func collectionPolicy(role models.CollectionRole, action collectionAction) error {
+	switch action {
+	case collectionRead:
+		return nil
+	case collectionEdit:
+		if !role.CanEdit() {
+			return errReadOnlyCollection
+		}
+		return nil
+	case collectionManage:
+		if role != models.CollectionRoleOwner {
+			return errNotCollectionOwner
+		}
+		return nil
+	case collectionReview:
+		if role != models.CollectionRoleOwner {
+			return errNotCollectionOwnerReview
+		}
+		return nil
+	}
+
+	return errNoCollectionAccess
+}
+
+// getCollectionAccess returns collection and role of the user in it,
+// only owner and members who accepted invitation have access
+func (a *App) getCollectionAccess(collectionId uint64, userId uint64) (*models.Collection, models.CollectionRole, error) {
+	collection, err := a.collectionRepo.GetById(collectionId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if collection == nil {
+		return nil, "", errCollectionNotFound
+	}
+
+	if collection.OwnerId == userId {
+		return collection, models.CollectionRoleOwner, nil
+	}
+
+	member, err := a.collectionMemberRepo.GetByCollectionAndUser(collectionId, userId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if member == nil || member.Status != models.MemberStatusAccepted {
+		return nil, "", errNoCollectionAccess
+	}
+
+	return collection, member.Role, nil
+}
+
+// authorizeCollection returns collection if user is allowed to do action with it
+func (a *App) authorizeCollection(collectionId uint64, userId uint64, action collectionAction) (*models.Collection, models.CollectionRole, error) {
+	collection, role, err := a.getCollectionAccess(collectionId, userId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = collectionPolicy(role, action)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return collection, role, nil
+}
+
+// collectionAccess loads collection from id param, checks access of the user
+// and puts collection with user role into context
+func (a *App) collectionAccess(param string, action collectionAction) func(ctx *gin.Context) {
+	return func(ctx *gin.Context) {
+		id := ctx.GetUint64(param)
+		if id == 0 {
+			newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+			return
+		}
+
+		user := a.getContextUser(ctx)
+		if user == nil {
+			return
+		}
+
+		collection, role, err := a.authorizeCollection(id, user.Id, action)
+		if err != nil {
+			collectionAccessErrorResponse(ctx, err)
+			return
+		}
+
+		ctx.Set("collection", collection)
+		ctx.Set("collectionRole", role)
+
+		ctx.Next()
+	}
+}
+
+func getContextCollection(ctx *gin.Context) *models.Collection {
+	collection, ok := ctx.Get("collection")
+	if !ok {
+		return nil
+	}
+
+	return collection.(*models.Collection)
+}
+
+func getContextCollectionRole(ctx *gin.Context) models.CollectionRole {
+	role, ok := ctx.Get("collectionRole")
+	if !ok {
+		return ""
+	}
+
+	return role.(models.CollectionRole)
+}
+
+// contextWordsCtx returns context for the words index of collection owner
+func contextWordsCtx(ctx *gin.Context) elastic.CollectionWordsOperationCtx {
+	collection := getContextCollection(ctx)
+
+	return elastic.CollectionWordsOperationCtx{UserId: collection.OwnerId, CollectionId: collection.Id}
+}
+
+// collectionWordsCtx does the same checks as collectionAccess for collection id from request body,
+// error response is sent on failure
+func (a *App) collectionWordsCtx(ctx *gin.Context, collectionId uint64, action collectionAction) (elastic.CollectionWordsOperationCtx, bool) {
+	if collectionId == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get collection id").Error())
+		return elastic.CollectionWordsOperationCtx{}, false
+	}
+
+	user := a.getContextUser(ctx)
+
+	collection, _, err := a.authorizeCollection(collectionId, user.Id, action)
+	if err != nil {
+		collectionAccessErrorResponse(ctx, err)
+		return elastic.CollectionWordsOperationCtx{}, false
+	}
+
+	return elastic.CollectionWordsOperationCtx{UserId: collection.OwnerId, CollectionId: collection.Id}, true
+}
+
+// getCollectionWord returns word from collection in context,
+// get by id ignores alias filter, so word of another owner collection or word in trash is not found too
+func (a *App) getCollectionWord(ctx *gin.Context, id string) (*models.Word, bool) {
+	wordsCtx := contextWordsCtx(ctx)
+
+	word, err := a.wordRepo.GetById(id, wordsCtx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	if word == nil || word.CollectionId != wordsCtx.CollectionId || word.DeletedAt != nil {
+		newErrorResponse(ctx, http.StatusNotFound, errWordNotFound.Error())
+		return nil, false
+	}
+
+	return word, true
+}
+
+func collectionAccessErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errCollectionNotFound):
+		newErrorResponse(ctx, http.StatusNotFound, err.Error())
+	case errors.Is(err, errNoCollectionAccess), errors.Is(err, errReadOnlyCollection), errors.Is(err, errNotCollectionOwner), errors.Is(err, errNotCollectionOwnerReview):
+		newErrorResponse(ctx, http.StatusForbidden, err.Error())
+	default:
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+	}
+}
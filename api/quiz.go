@@ -0,0 +1,241 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"time"
+	"vacabulary/models"
+	"vacabulary/pkg/quiz"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultQuizSize = 10
+)
+
+func (a *App) InjectQuiz(gr *gin.Engine) {
+	quizzes := gr.Group("/collection", a.authorizeRequest)
+
+	quizzes.POST(":id/quiz", a.idParam("id"), a.collectionAccess("id", collectionRead), a.createQuiz)
+	quizzes.POST(":id/quiz/answers", a.idParam("id"), a.collectionAccess("id", collectionRead), a.checkQuizAnswers)
+}
+
+type createQuizInp struct {
+	Mode models.QuizMode `json:"mode"`
+	Size int             `json:"size"`
+}
+
+type createQuizResponse struct {
+	SessionId uint64                `json:"sessionId"`
+	Mode      models.QuizMode       `json:"mode"`
+	Questions []models.QuizQuestion `json:"questions"`
+}
+
+func (a *App) createQuiz(ctx *gin.Context) {
+	id := ctx.GetUint64("id")
+	if id == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	var input createQuizInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if input.Mode == "" {
+		input.Mode = models.QuizModeMixed
+	}
+
+	if !input.Mode.IsValid() {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("mode must be one of multipleChoice, typing, reverse, mixed").Error())
+		return
+	}
+
+	if input.Size <= 0 {
+		input.Size = defaultQuizSize
+	}
+
+	wordsCtx := contextWordsCtx(ctx)
+
+	user := a.getContextUser(ctx)
+
+	words, err := a.wordRepo.GetAllWords(wordsCtx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	questions, err := a.quizGenerator.Generate(words, input.Mode, input.Size)
+	if err != nil {
+		if errors.Is(err, quiz.ErrNotEnoughWords) {
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// modes are kept in the session, so answers are graded by generated questions
+	sessionQuestions := []models.StudySessionQuestion{}
+	for _, q := range questions {
+		sessionQuestions = append(sessionQuestions, models.StudySessionQuestion{WordId: q.WordId, Mode: q.Mode})
+	}
+
+	session, err := a.studySessionRepo.Create(models.StudySession{
+		UserId:       user.Id,
+		CollectionId: id,
+		Mode:         string(input.Mode),
+		StartedAt:    time.Now(),
+		Questions:    sessionQuestions,
+	})
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createQuizResponse{
+		SessionId: session.Id,
+		Mode:      input.Mode,
+		Questions: questions,
+	})
+}
+
+type checkQuizAnswersInp struct {
+	SessionId uint64              `json:"sessionId"`
+	Answers   []models.QuizAnswer `json:"answers"`
+}
+
+func (a *App) checkQuizAnswers(ctx *gin.Context) {
+	id := ctx.GetUint64("id")
+	if id == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	var input checkQuizAnswersInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if input.SessionId == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("sessionId is required").Error())
+		return
+	}
+
+	if len(input.Answers) == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("answers can't be empty").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	session, err := a.getUserSession(input.SessionId, user.Id)
+	if err != nil {
+		if errors.Is(err, errSessionNotFound) {
+			newErrorResponse(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if session.CollectionId != id || len(session.Questions) == 0 {
+		newErrorResponse(ctx, http.StatusNotFound, errSessionNotFound.Error())
+		return
+	}
+
+	if session.FinishedAt != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, errSessionFinished.Error())
+		return
+	}
+
+	questionIds := map[string]bool{}
+	for _, q := range session.Questions {
+		questionIds[q.WordId] = true
+	}
+
+	// only the first answer of the question is graded
+	answers := map[string]models.QuizAnswer{}
+	for _, answer := range input.Answers {
+		if _, ok := answers[answer.WordId]; questionIds[answer.WordId] && !ok {
+			answers[answer.WordId] = answer
+		}
+	}
+
+	if len(answers) == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("answers don't match questions of the quiz").Error())
+		return
+	}
+
+	wordsCtx := contextWordsCtx(ctx)
+
+	// questions without answer are graded as wrong, so score is counted by all questions of the quiz
+	result := models.QuizResult{
+		Total:   uint64(len(session.Questions)),
+		Results: []models.QuizQuestionResult{},
+	}
+	for _, q := range session.Questions {
+		answer := answers[q.WordId]
+		answer.WordId = q.WordId
+		// mode of the answer is taken from generated question, client can't choose easier one
+		answer.Mode = q.Mode
+
+		word, err := a.wordRepo.GetById(answer.WordId, wordsCtx)
+		if err != nil || word == nil || word.CollectionId != id {
+			result.Results = append(result.Results, models.QuizQuestionResult{
+				WordId:   answer.WordId,
+				Mode:     answer.Mode,
+				Answer:   answer.Answer,
+				Feedback: "word not found",
+			})
+			continue
+		}
+
+		questionResult := quiz.Grade(*word, answer)
+		if questionResult.Correct {
+			result.Correct++
+		}
+
+		result.Results = append(result.Results, questionResult)
+	}
+
+	result.Score = math.Round(float64(result.Correct)/float64(result.Total)*10000) / 100
+
+	answeredAt := time.Now()
+
+	var sessionAnswers []models.StudySessionAnswer
+	for _, r := range result.Results {
+		word := r.Prompt
+		if r.Mode == models.QuizModeReverse {
+			word = r.CorrectAnswer
+		}
+
+		sessionAnswers = append(sessionAnswers, models.StudySessionAnswer{
+			WordId:     r.WordId,
+			Word:       word,
+			Correct:    r.Correct,
+			AnsweredAt: answeredAt,
+		})
+	}
+
+	err = a.recordSessionAnswers(input.SessionId, user.Id, id, sessionAnswers)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = a.studySessionRepo.Finish(input.SessionId, answeredAt)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"vacabulary/models"
+	"vacabulary/pkg/scheduler"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultReviewQueueSize = 20
+)
+
+// InjectReview adds review of words by schedule, progress is kept in words of the collection owner,
+// so members of shared collection get 403 and study it with quiz instead
+func (a *App) InjectReview(gr *gin.Engine) {
+	review := gr.Group("/word", a.authorizeRequest)
+
+	review.GET("/collection/:collectionId/review", a.idParam("collectionId"), a.collectionAccess("collectionId", collectionReview), a.getReviewQueue)
+	review.POST(":id/collection/:collectionId/review", a.idParam("collectionId"), a.collectionAccess("collectionId", collectionReview), a.reviewWord)
+}
+
+type getReviewQueueResponse struct {
+	Words    []models.Word `json:"words"`
+	TotalDue uint64        `json:"totalDue"`
+}
+
+func (a *App) getReviewQueue(ctx *gin.Context) {
+	size := uint64(defaultReviewQueueSize)
+	if sizeStr := ctx.Query("size"); sizeStr != "" {
+		parsedSize, err := strconv.ParseUint(sizeStr, 10, 64)
+		if err != nil || parsedSize == 0 {
+			newErrorResponse(ctx, http.StatusBadRequest, errors.New("size not valid").Error())
+			return
+		}
+		size = parsedSize
+	}
+
+	words, totalDue, err := a.wordRepo.GetDue(time.Now(), size, contextWordsCtx(ctx))
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if words == nil {
+		words = []models.Word{}
+	}
+
+	ctx.JSON(http.StatusOK, getReviewQueueResponse{
+		Words:    words,
+		TotalDue: totalDue,
+	})
+}
+
+type reviewWordInp struct {
+	Grade     models.ReviewGrade `json:"grade"`
+	SessionId uint64             `json:"sessionId"`
+}
+
+func (a *App) reviewWord(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	var input reviewWordInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !input.Grade.IsValid() {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("grade must be one of again, hard, good, easy").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+	collection := getContextCollection(ctx)
+
+	word, ok := a.getCollectionWord(ctx, id)
+	if !ok {
+		return
+	}
+
+	wordScheduler, err := scheduler.NewScheduler(collection.SchedulerSettings)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	reviewedAt := time.Now()
+
+	progress, err := wordScheduler.Schedule(word.Progress, input.Grade, reviewedAt)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// session is checked before progress is changed, answer is stored only after progress is saved
+	if input.SessionId != 0 {
+		_, err = a.getActiveSession(input.SessionId, user.Id, collection.Id)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	err = a.wordRepo.UpdateProgress(id, progress, contextWordsCtx(ctx))
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// review without session is stored too, so it is counted in activity and streak
+	answer := models.StudySessionAnswer{
+		UserId:       user.Id,
+		CollectionId: collection.Id,
+		WordId:       id,
+		Word:         word.Word,
+		Correct:      input.Grade != models.ReviewGradeAgain,
+		AnsweredAt:   reviewedAt,
+	}
+
+	if input.SessionId != 0 {
+		err = a.studySessionRepo.AddAnswers(input.SessionId, []models.StudySessionAnswer{answer})
+	} else {
+		err = a.studySessionRepo.AddAnswer(answer)
+	}
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":  "success",
+		"progress": progress,
+	})
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"vacabulary/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultMistakesLimit = 20
+)
+
+var (
+	errSessionNotFound = errors.New("study session not found")
+	errSessionFinished = errors.New("study session already finished")
+)
+
+func (a *App) InjectSessions(gr *gin.Engine) {
+	sessions := gr.Group("/session", a.authorizeRequest)
+
+	sessions.POST("", a.startSession)
+	sessions.GET("/all", a.getAllSessions)
+	sessions.GET(":id", a.idParam("id"), a.getSession)
+	sessions.POST(":id/finish", a.idParam("id"), a.finishSession)
+
+	collections := gr.Group("/collection", a.authorizeRequest)
+
+	collections.GET(":id/mistakes", a.idParam("id"), a.collectionAccess("id", collectionRead), a.getMostMissedWords)
+}
+
+type startSessionInp struct {
+	CollectionId uint64 `json:"collectionId"`
+	Mode         string `json:"mode"`
+}
+
+func (a *App) startSession(ctx *gin.Context) {
+	var input startSessionInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if input.CollectionId == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get collection id").Error())
+		return
+	}
+
+	if input.Mode == "" {
+		input.Mode = models.StudySessionModeReview
+	}
+
+	_, ok := a.collectionWordsCtx(ctx, input.CollectionId, collectionRead)
+	if !ok {
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	session, err := a.studySessionRepo.Create(models.StudySession{
+		UserId:       user.Id,
+		CollectionId: input.CollectionId,
+		Mode:         input.Mode,
+		StartedAt:    time.Now(),
+	})
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success",
+		"session": session,
+	})
+}
+
+type getAllSessionsResponse struct {
+	Sessions []models.StudySession `json:"sessions"`
+}
+
+func (a *App) getAllSessions(ctx *gin.Context) {
+	var collectionId uint64
+	if collectionIdStr := ctx.Query("collectionId"); collectionIdStr != "" {
+		id, err := strconv.ParseUint(collectionIdStr, 10, 64)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusBadRequest, errors.New("collection id not valid").Error())
+			return
+		}
+		collectionId = id
+	}
+
+	user := a.getContextUser(ctx)
+
+	sessions, err := a.studySessionRepo.GetByUserId(user.Id, collectionId)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getAllSessionsResponse{
+		Sessions: sessions,
+	})
+}
+
+type getSessionResponse struct {
+	Session *models.StudySession `json:"session"`
+}
+
+func (a *App) getSession(ctx *gin.Context) {
+	id := ctx.GetUint64("id")
+	if id == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	session, err := a.getUserSession(id, user.Id)
+	if err != nil {
+		if errors.Is(err, errSessionNotFound) {
+			newErrorResponse(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getSessionResponse{
+		Session: session,
+	})
+}
+
+func (a *App) finishSession(ctx *gin.Context) {
+	id := ctx.GetUint64("id")
+	if id == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	session, err := a.getUserSession(id, user.Id)
+	if err != nil {
+		if errors.Is(err, errSessionNotFound) {
+			newErrorResponse(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if session.FinishedAt != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, errSessionFinished.Error())
+		return
+	}
+
+	err = a.studySessionRepo.Finish(id, time.Now())
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success",
+	})
+}
+
+type missedWordResponse struct {
+	models.MissedWord
+	Details *models.Word `json:"details"`
+}
+
+type getMostMissedWordsResponse struct {
+	Words []missedWordResponse `json:"words"`
+}
+
+func (a *App) getMostMissedWords(ctx *gin.Context) {
+	id := ctx.GetUint64("id")
+	if id == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	limit := uint64(defaultMistakesLimit)
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		parsedLimit, err := strconv.ParseUint(limitStr, 10, 64)
+		if err != nil || parsedLimit == 0 {
+			newErrorResponse(ctx, http.StatusBadRequest, errors.New("limit not valid").Error())
+			return
+		}
+		limit = parsedLimit
+	}
+
+	wordsCtx := contextWordsCtx(ctx)
+
+	user := a.getContextUser(ctx)
+
+	missedWords, err := a.studySessionRepo.GetMostMissedWords(user.Id, id, limit)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	words := []missedWordResponse{}
+	for _, m := range missedWords {
+		// word could be deleted after the session, then only logged data is returned
+		word, err := a.wordRepo.GetById(m.WordId, wordsCtx)
+		if err != nil {
+			word = nil
+		}
+
+		words = append(words, missedWordResponse{
+			MissedWord: m,
+			Details:    word,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, getMostMissedWordsResponse{
+		Words: words,
+	})
+}
+
+// getUserSession returns session only if it belongs to the user
+func (a *App) getUserSession(id uint64, userId uint64) (*models.StudySession, error) {
+	session, err := a.studySessionRepo.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil || session.UserId != userId {
+		return nil, errSessionNotFound
+	}
+
+	return session, nil
+}
+
+// getActiveSession returns session of the user which is started for the collection and not finished yet
+func (a *App) getActiveSession(sessionId uint64, userId uint64, collectionId uint64) (*models.StudySession, error) {
+	session, err := a.getUserSession(sessionId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.CollectionId != collectionId {
+		return nil, errSessionNotFound
+	}
+
+	if session.FinishedAt != nil {
+		return nil, errSessionFinished
+	}
+
+	return session, nil
+}
+
+// recordSessionAnswers stores answers to the active session of the collection
+func (a *App) recordSessionAnswers(sessionId uint64, userId uint64, collectionId uint64, answers []models.StudySessionAnswer) error {
+	_, err := a.getActiveSession(sessionId, userId, collectionId)
+	if err != nil {
+		return err
+	}
+
+	for i := range answers {
+		answers[i].UserId = userId
+		answers[i].CollectionId = collectionId
+	}
+
+	return a.studySessionRepo.AddAnswers(sessionId, answers)
+}
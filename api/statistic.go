@@ -84,7 +84,7 @@ func (a *App) getCountOfWordsPerTime(ctx *gin.Context) {
 		userIds = append(userIds, u.Id)
 	}
 
-	countOfWordsPerTime, err := a.wordRepo.GetCountOfWordsPerTime(userIds, perTime)
+	countOfWordsPerTime, err := a.wordRepo.GetCountOfWordsPerTime(userIds, perTime, "UTC")
 	if err != nil {
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
@@ -118,7 +118,7 @@ func (a *App) searchWordsInAllCollections(ctx *gin.Context) {
 			})
 			return
 		}
-		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
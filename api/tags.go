@@ -0,0 +1,149 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"vacabulary/models"
+	"vacabulary/repositories/elastic"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (a *App) InjectTags(gr *gin.Engine) {
+	tags := gr.Group("/tag", a.authorizeRequest)
+
+	tags.GET("/all", a.getAllTags)
+	tags.PUT("/rename", a.renameTag)
+	tags.PUT("/merge", a.mergeTags)
+	tags.DELETE("", a.deleteTag)
+}
+
+type getAllTagsResponse struct {
+	Tags []models.TagCount `json:"tags"`
+}
+
+func (a *App) getAllTags(ctx *gin.Context) {
+	user := a.getContextUser(ctx)
+
+	tags, err := a.wordRepo.GetTags(user.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getAllTagsResponse{
+		Tags: tags,
+	})
+}
+
+type renameTagInp struct {
+	Name    string `json:"name"`
+	NewName string `json:"newName"`
+}
+
+func (a *App) renameTag(ctx *gin.Context) {
+	var input renameTagInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	input.Name = strings.TrimSpace(input.Name)
+	input.NewName = strings.TrimSpace(input.NewName)
+	if input.Name == "" || input.NewName == "" {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("tag name and new name can't be empty").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	updated, err := a.wordRepo.ReplaceTags(user.Id, []string{input.Name}, input.NewName)
+	if errors.Is(err, elastic.ErrTagsConflict) {
+		newErrorResponse(ctx, http.StatusConflict, fmt.Errorf("%w, %d words updated", err, updated).Error())
+		return
+	}
+
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success update",
+		"updated": updated,
+	})
+}
+
+type mergeTagsInp struct {
+	Tags []string `json:"tags"`
+	Into string   `json:"into"`
+}
+
+func (a *App) mergeTags(ctx *gin.Context) {
+	var input mergeTagsInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var tags []string
+	for _, t := range input.Tags {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+
+	input.Into = strings.TrimSpace(input.Into)
+	if len(tags) == 0 || input.Into == "" {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("tags and target tag can't be empty").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	updated, err := a.wordRepo.ReplaceTags(user.Id, tags, input.Into)
+	if errors.Is(err, elastic.ErrTagsConflict) {
+		newErrorResponse(ctx, http.StatusConflict, fmt.Errorf("%w, %d words updated", err, updated).Error())
+		return
+	}
+
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success update",
+		"updated": updated,
+	})
+}
+
+func (a *App) deleteTag(ctx *gin.Context) {
+	name := strings.TrimSpace(ctx.Query("name"))
+	if name == "" {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get tag name").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	updated, err := a.wordRepo.ReplaceTags(user.Id, []string{name}, "")
+	if errors.Is(err, elastic.ErrTagsConflict) {
+		newErrorResponse(ctx, http.StatusConflict, fmt.Errorf("%w, %d words updated", err, updated).Error())
+		return
+	}
+
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success delete",
+		"updated": updated,
+	})
+}
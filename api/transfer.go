@@ -0,0 +1,325 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"vacabulary/models"
+	"vacabulary/repositories/elastic"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errCollectionNotFound   = errors.New("collection not found")
+	errSameCollection       = errors.New("source and target collections must be different")
+	errLanguagePairMismatch = errors.New("collections have different language pairs")
+)
+
+func (a *App) InjectTransfer(gr *gin.Engine) {
+	words := gr.Group("/word", a.authorizeRequest)
+
+	words.POST("/move", a.moveWords)
+	words.POST("/copy", a.copyWords)
+
+	collections := gr.Group("/collection", a.authorizeRequest)
+
+	collections.POST(":id/merge", a.idParam("id"), a.mergeCollection)
+}
+
+type transferWordsInp struct {
+	WordIds               []string                `json:"wordIds"`
+	FromCollectionId      uint64                  `json:"fromCollectionId"`
+	ToCollectionId        uint64                  `json:"toCollectionId"`
+	Strategy              models.ConflictStrategy `json:"strategy"`
+	AllowLanguageMismatch bool                    `json:"allowLanguageMismatch"`
+}
+
+func (a *App) moveWords(ctx *gin.Context) {
+	a.transferWordsByIds(ctx, true)
+}
+
+func (a *App) copyWords(ctx *gin.Context) {
+	a.transferWordsByIds(ctx, false)
+}
+
+func (a *App) transferWordsByIds(ctx *gin.Context, move bool) {
+	var input transferWordsInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(input.WordIds) == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("word ids can't be empty").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	source, target, err := a.getTransferCollections(user.Id, input.FromCollectionId, input.ToCollectionId, input.AllowLanguageMismatch)
+	if err != nil {
+		a.transferErrorResponse(ctx, err)
+		return
+	}
+
+	strategy, err := getConflictStrategy(input.Strategy)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sourceCtx := elastic.CollectionWordsOperationCtx{UserId: user.Id, CollectionId: source.Id}
+
+	var words []models.Word
+	for _, id := range input.WordIds {
+		word, err := a.wordRepo.GetById(id, sourceCtx)
+		if err != nil || word == nil || word.CollectionId != source.Id || word.DeletedAt != nil {
+			newErrorResponse(ctx, http.StatusNotFound, errors.New("word "+id+" not found in source collection").Error())
+			return
+		}
+
+		words = append(words, *word)
+	}
+
+	result, err := a.transferWords(user.Id, words, source, target, strategy, move)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success",
+		"result":  result,
+	})
+}
+
+type mergeCollectionInp struct {
+	IntoCollectionId      uint64                  `json:"intoCollectionId"`
+	Strategy              models.ConflictStrategy `json:"strategy"`
+	AllowLanguageMismatch bool                    `json:"allowLanguageMismatch"`
+	DeleteSource          bool                    `json:"deleteSource"`
+}
+
+func (a *App) mergeCollection(ctx *gin.Context) {
+	id := ctx.GetUint64("id")
+	if id == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return
+	}
+
+	var input mergeCollectionInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	source, target, err := a.getTransferCollections(user.Id, id, input.IntoCollectionId, input.AllowLanguageMismatch)
+	if err != nil {
+		a.transferErrorResponse(ctx, err)
+		return
+	}
+
+	strategy, err := getConflictStrategy(input.Strategy)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sourceCtx := elastic.CollectionWordsOperationCtx{UserId: user.Id, CollectionId: source.Id}
+
+	words, err := a.wordRepo.GetAllWords(sourceCtx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	result, err := a.transferWords(user.Id, words, source, target, strategy, true)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// skipped words stay in the source collection, so it is kept
+	sourceDeleted := false
+	remainingWords := len(result.Skipped)
+	var deletion *models.CollectionDeletion
+	if input.DeleteSource && len(result.Skipped) == 0 {
+		// source is deleted only when all its words are moved, words added during merge
+		// and words in trash would be lost with it
+		remainingIds, err := a.wordRepo.GetIdsByCollectionId(sourceCtx)
+		if err != nil {
+			newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		remainingWords = len(remainingIds)
+		if remainingWords == 0 {
+			deletion, err = a.deleteCollectionCascade(*source)
+			if err != nil {
+				newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+				return
+			}
+			sourceDeleted = true
+		}
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":        "success",
+		"result":         result,
+		"sourceDeleted":  sourceDeleted,
+		"remainingWords": remainingWords,
+		"deletion":       deletion,
+	})
+}
+
+// transferWords moves or copies words to the target collection resolving
+// conflicts with words which already exist there by the strategy
+func (a *App) transferWords(userId uint64, words []models.Word, source, target *models.Collection, strategy models.ConflictStrategy, move bool) (*models.WordsTransferResult, error) {
+	result := models.WordsTransferResult{
+		Skipped: []string{},
+	}
+
+	if len(words) == 0 {
+		return &result, nil
+	}
+
+	sourceCtx := elastic.CollectionWordsOperationCtx{UserId: userId, CollectionId: source.Id}
+	targetCtx := elastic.CollectionWordsOperationCtx{UserId: userId, CollectionId: target.Id}
+
+	var wordsWord []string
+	for _, w := range words {
+		wordsWord = append(wordsWord, w.Word)
+	}
+
+	// check: such words already esists in target collection or not
+	existingWords, err := a.wordRepo.GetByWords(wordsWord, targetCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	existingByWord := map[string]models.Word{}
+	for _, w := range existingWords {
+		existingByWord[strings.ToLower(strings.TrimSpace(w.Word))] = w
+	}
+
+	var copies []models.Word
+	for _, word := range words {
+		existing, exists := existingByWord[strings.ToLower(strings.TrimSpace(word.Word))]
+
+		if exists && strategy == models.ConflictStrategySkip {
+			result.Skipped = append(result.Skipped, word.Word)
+			continue
+		}
+
+		if exists && strategy == models.ConflictStrategyOverwrite {
+			overwritten := word
+			overwritten.Id = existing.Id
+			overwritten.CollectionId = target.Id
+			overwritten.CreatedAt = existing.CreatedAt
+
+			err = a.wordRepo.Update(overwritten, targetCtx)
+			if err != nil {
+				return nil, err
+			}
+
+			// source word is replaced by the target one, so it is removed with its history
+			if move {
+				err = a.wordHistoryRepo.DeleteByWordIds([]string{word.Id})
+				if err != nil {
+					return nil, err
+				}
+
+				err = a.wordRepo.DeleteById(word.Id, sourceCtx)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			result.Overwritten++
+			continue
+		}
+
+		if move {
+			// all user collections share the same index
+			err = a.wordRepo.UpdateCollectionId(word.Id, target.Id, sourceCtx)
+			if err != nil {
+				return nil, err
+			}
+
+			err = a.wordHistoryRepo.UpdateCollectionId(word.Id, target.Id)
+			if err != nil {
+				return nil, err
+			}
+
+			result.Transferred++
+			continue
+		}
+
+		copied := word
+		copied.Id = ""
+		copied.CollectionId = target.Id
+		copied.Progress = models.WordProgress{}
+
+		copies = append(copies, copied)
+	}
+
+	if len(copies) > 0 {
+		err = a.wordRepo.BulkCreate(copies, targetCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Transferred += uint64(len(copies))
+	}
+
+	return &result, nil
+}
+
+// getTransferCollections returns user collections and checks they can be used together
+func (a *App) getTransferCollections(userId uint64, sourceId uint64, targetId uint64, allowLanguageMismatch bool) (*models.Collection, *models.Collection, error) {
+	if sourceId == targetId {
+		return nil, nil, errSameCollection
+	}
+
+	source, _, err := a.authorizeCollection(sourceId, userId, collectionManage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target, _, err := a.authorizeCollection(targetId, userId, collectionManage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !allowLanguageMismatch && (source.LangFrom != target.LangFrom || source.LangTo != target.LangTo) {
+		return nil, nil, errLanguagePairMismatch
+	}
+
+	return source, target, nil
+}
+
+func (a *App) transferErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errSameCollection), errors.Is(err, errLanguagePairMismatch):
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+	default:
+		collectionAccessErrorResponse(ctx, err)
+	}
+}
+
+func getConflictStrategy(strategy models.ConflictStrategy) (models.ConflictStrategy, error) {
+	if strategy == "" {
+		return models.ConflictStrategySkip, nil
+	}
+
+	if !strategy.IsValid() {
+		return "", errors.New("strategy must be one of skip, overwrite, keepBoth")
+	}
+
+	return strategy, nil
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"vacabulary/models"
+	"vacabulary/pkg/translator"
+	"vacabulary/repositories/elastic"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// translation is failed after this count of attempts
+	maxTranslationAttempts = 3
+	// processing job is returned to the queue when worker doesn't finish it in time
+	translationStaleTimeout = 30 * time.Minute
+	// finished jobs are removed after this period
+	translationRetention = 24 * time.Hour
+	// failed translation is retried after this delay, it is doubled after every attempt
+	translationRetryDelay = time.Minute
+)
+
+var (
+	errTranslationNotFound = errors.New("translation not found")
+)
+
+func (a *App) InjectTranslations(gr *gin.Engine) {
+	translations := gr.Group("/translations", a.authorizeRequest)
+	translations.GET(":id", a.idParam("id"), a.getTranslationJob)
+}
+
+func (a *App) getTranslationJob(ctx *gin.Context) {
+	user := a.getContextUser(ctx)
+	id := ctx.GetUint64("id")
+
+	job, err := a.translationJobRepo.GetById(id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if job == nil || job.UserId != user.Id {
+		newErrorResponse(ctx, http.StatusNotFound, errTranslationNotFound.Error())
+		return
+	}
+
+	// user can lose access to shared collection after import
+	_, _, err = a.authorizeCollection(job.CollectionId, user.Id, collectionRead)
+	if err != nil {
+		collectionAccessErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
+
+// createTranslationJob queues translation of imported words, worker is notified right away
+func (a *App) createTranslationJob(words []string, collection *models.Collection, userId uint64) (*models.TranslationJob, error) {
+	job, err := a.translationJobRepo.Create(models.TranslationJob{
+		CollectionId: collection.Id,
+		OwnerId:      collection.OwnerId,
+		UserId:       userId,
+		Words:        words,
+		Status:       models.TranslationStatusPending,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notifyJobRunner(a.translationJobsQueue)
+
+	return job, nil
+}
+
+// RunTranslationJobs translates words of pending jobs, returns stale jobs to the queue
+// and removes finished jobs, it blocks forever
+func (a *App) RunTranslationJobs(interval time.Duration) {
+	jobRunner[models.TranslationJob]{
+		name:  "translations",
+		queue: a.translationJobsQueue,
+		drain: true,
+		resetStale: func(now time.Time) error {
+			return a.translationJobRepo.ResetStale(now.Add(-translationStaleTimeout), maxTranslationAttempts)
+		},
+		take: func(now time.Time) ([]models.TranslationJob, error) {
+			return takeOne(a.translationJobRepo.TakePending(now))
+		},
+		run: a.runTranslationJob,
+		cleanup: func(now time.Time) error {
+			return a.translationJobRepo.DeleteFinished(now.Add(-translationRetention))
+		},
+	}.Run(interval)
+}
+
+func (a *App) runTranslationJob(job *models.TranslationJob) {
+	err := a.translateJobWords(job)
+	now := time.Now()
+
+	switch {
+	case err == nil:
+		job.Status = models.TranslationStatusCompleted
+		job.Error = ""
+		job.NextAttemptAt = nil
+		job.FinishedAt = &now
+	// retry doesn't help when collection is removed or languages are not supported
+	case job.Attempts >= maxTranslationAttempts || errors.Is(err, errCollectionNotFound) || errors.Is(err, translator.ErrUnsupportedLanguage):
+		fmt.Printf("failed to translate words of collection %d: %s\n", job.CollectionId, err.Error())
+
+		job.Status = models.TranslationStatusFailed
+		job.Error = err.Error()
+		job.FinishedAt = &now
+	default:
+		fmt.Printf("failed to translate words of collection %d: %s\n", job.CollectionId, err.Error())
+
+		nextAttemptAt := retryAt(now, translationRetryDelay, job.Attempts)
+
+		job.Status = models.TranslationStatusPending
+		job.Error = err.Error()
+		job.NextAttemptAt = &nextAttemptAt
+	}
+
+	err = a.translationJobRepo.Update(*job)
+	if err != nil {
+		fmt.Printf("failed to update translation %d: %s\n", job.Id, err.Error())
+	}
+}
+
+// translateJobWords translates words of the job which still have no translation, so words
+// translated by previous attempt or edited by user are skipped. Words without translation
+// are kept as is, the first failure of translator stops the attempt.
+func (a *App) translateJobWords(job *models.TranslationJob) error {
+	collection, err := a.collectionRepo.GetById(job.CollectionId)
+	if err != nil {
+		return err
+	}
+
+	if collection == nil || collection.DeletedAt != nil {
+		return errCollectionNotFound
+	}
+
+	wordsCtx := elastic.CollectionWordsOperationCtx{UserId: collection.OwnerId, CollectionId: collection.Id}
+
+	words, err := a.wordRepo.GetByWords(job.Words, wordsCtx)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range words {
+		if w.Translation != "" {
+			continue
+		}
+
+		translation, err := a.translator.TranslateWord(w.Word, collection.LangFrom, collection.LangTo)
+		if errors.Is(err, translator.ErrNoTranslation) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if translation == "" {
+			continue
+		}
+
+		// word is read again, so changes made during translation are not overwritten
+		word, err := a.wordRepo.GetById(w.Id, wordsCtx)
+		if err != nil {
+			return err
+		}
+
+		if word == nil || word.Translation != "" {
+			continue
+		}
+
+		word.Translation = translation
+		err = a.wordRepo.Update(*word, wordsCtx)
+		if err != nil {
+			return err
+		}
+
+		job.Translated++
+	}
+
+	return nil
+}
@@ -0,0 +1,287 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"vacabulary/config"
+	"vacabulary/models"
+	"vacabulary/repositories/elastic"
+	"vacabulary/repositories/postgres"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errNotInTrash = errors.New("item not found in trash")
+)
+
+func (a *App) InjectTrash(gr *gin.Engine) {
+	trash := gr.Group("/trash", a.authorizeRequest)
+
+	trash.GET("", a.getTrash)
+	trash.POST("/collection/:id/restore", a.idParam("id"), a.restoreCollection)
+	trash.DELETE("/collection/:id", a.idParam("id"), a.purgeCollection)
+	trash.POST("/word/:id/restore", a.restoreWord)
+	trash.DELETE("/word/:id", a.purgeWord)
+}
+
+type getTrashResponse struct {
+	Collections   []models.Collection `json:"collections"`
+	Words         []models.Word       `json:"words"`
+	RetentionDays uint64              `json:"retentionDays"`
+}
+
+// getTrash returns deleted collections and words deleted from not deleted collections,
+// words of deleted collection are restored with it
+func (a *App) getTrash(ctx *gin.Context) {
+	user := a.getContextUser(ctx)
+
+	collections, err := a.collectionRepo.GetDeletedByOwnerId(user.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	words, err := a.wordRepo.GetDeleted(user.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	deletedCollections := map[uint64]bool{}
+	for _, c := range collections {
+		deletedCollections[c.Id] = true
+	}
+
+	trashWords := []models.Word{}
+	for _, w := range words {
+		if deletedCollections[w.CollectionId] {
+			continue
+		}
+		trashWords = append(trashWords, w)
+	}
+
+	ctx.JSON(http.StatusOK, getTrashResponse{
+		Collections:   collections,
+		Words:         trashWords,
+		RetentionDays: uint64(config.Config.Trash.Retention().Hours() / 24),
+	})
+}
+
+func (a *App) restoreCollection(ctx *gin.Context) {
+	collection, ok := a.getTrashCollection(ctx)
+	if !ok {
+		return
+	}
+
+	err := a.collectionRepo.Restore(collection.Id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrCollectionNameExists) {
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	err = a.wordRepo.RestoreByCollectionId(elastic.CollectionWordsOperationCtx{UserId: collection.OwnerId, CollectionId: collection.Id})
+	if err != nil {
+		// collection is returned to trash, so its words are not lost from the trash
+		deleteErr := a.collectionRepo.SoftDeleteById(collection.Id, *collection.DeletedAt)
+		if deleteErr != nil {
+			fmt.Printf("failed to return collection %d to trash: %s\n", collection.Id, deleteErr.Error())
+		}
+
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	collection.DeletedAt = nil
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":    "success",
+		"collection": collection,
+	})
+}
+
+func (a *App) purgeCollection(ctx *gin.Context) {
+	collection, ok := a.getTrashCollection(ctx)
+	if !ok {
+		return
+	}
+
+	deletion, err := a.deleteCollectionCascade(*collection)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":  "success delete",
+		"deletion": deletion,
+	})
+}
+
+func (a *App) restoreWord(ctx *gin.Context) {
+	word, ok := a.getTrashWord(ctx)
+	if !ok {
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	collection, err := a.collectionRepo.GetById(word.CollectionId)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if collection == nil {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("collection of the word is deleted, restore collection first").Error())
+		return
+	}
+
+	wordsCtx := elastic.CollectionWordsOperationCtx{UserId: user.Id, CollectionId: collection.Id}
+
+	// same word could be added again after deletion
+	sameWord, err := a.wordRepo.Get(word.Word, wordsCtx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if sameWord != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("such word already esists").Error())
+		return
+	}
+
+	err = a.wordRepo.RestoreById(word.Id, wordsCtx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	word.DeletedAt = nil
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success",
+		"word":    word,
+	})
+}
+
+func (a *App) purgeWord(ctx *gin.Context) {
+	word, ok := a.getTrashWord(ctx)
+	if !ok {
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	err := a.wordRepo.DeleteById(word.Id, elastic.CollectionWordsOperationCtx{UserId: user.Id})
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	err = a.wordHistoryRepo.DeleteByWordId(word.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message": "success delete",
+	})
+}
+
+func (a *App) getTrashCollection(ctx *gin.Context) (*models.Collection, bool) {
+	id := ctx.GetUint64("id")
+	if id == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return nil, false
+	}
+
+	user := a.getContextUser(ctx)
+
+	collection, err := a.collectionRepo.GetDeletedById(id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	if collection == nil || collection.OwnerId != user.Id {
+		newErrorResponse(ctx, http.StatusNotFound, errNotInTrash.Error())
+		return nil, false
+	}
+
+	return collection, true
+}
+
+// getTrashWord returns deleted word from index of the user, words deleted by
+// editors of shared collection are in the trash of collection owner
+func (a *App) getTrashWord(ctx *gin.Context) (*models.Word, bool) {
+	id := ctx.Param("id")
+	if id == "" {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get id").Error())
+		return nil, false
+	}
+
+	user := a.getContextUser(ctx)
+
+	word, err := a.wordRepo.GetById(id, elastic.CollectionWordsOperationCtx{UserId: user.Id})
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	if word == nil || word.DeletedAt == nil {
+		newErrorResponse(ctx, http.StatusNotFound, errNotInTrash.Error())
+		return nil, false
+	}
+
+	return word, true
+}
+
+// PurgeTrash periodically removes words and collections which are in trash
+// longer than retention period, it blocks forever
+func (a *App) PurgeTrash(retention time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		deletedBefore := time.Now().Add(-retention)
+
+		collections, err := a.collectionRepo.GetDeletedBefore(deletedBefore)
+		if err != nil {
+			fmt.Printf("failed to get collections for purge: %s\n", err.Error())
+		}
+
+		for _, c := range collections {
+			_, err = a.deleteCollectionCascade(c)
+			if err != nil {
+				fmt.Printf("failed to purge collection %d: %s\n", c.Id, err.Error())
+			}
+		}
+
+		users, err := a.userRepo.GetAll()
+		if err != nil {
+			fmt.Printf("failed to get users for purge: %s\n", err.Error())
+			continue
+		}
+
+		for _, u := range users {
+			wordIds, err := a.wordRepo.PurgeDeleted(u.Id, deletedBefore)
+			if err != nil {
+				fmt.Printf("failed to purge words of user %d: %s\n", u.Id, err.Error())
+				continue
+			}
+
+			err = a.wordHistoryRepo.DeleteByWordIds(wordIds)
+			if err != nil {
+				fmt.Printf("failed to purge history of words of user %d: %s\n", u.Id, err.Error())
+			}
+		}
+	}
+}
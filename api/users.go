@@ -2,17 +2,22 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"time"
 	"vacabulary/config"
 	"vacabulary/db/elastic"
 	"vacabulary/models"
+	"vacabulary/pkg/streak"
 
 	"github.com/gin-gonic/gin"
 )
 
 const (
 	expirationTime = 24 * time.Hour
+
+	// streakDays limits activity loaded to calculate streak
+	streakDays = 365
 )
 
 func (a *App) InjectUsers(gr *gin.Engine) {
@@ -20,12 +25,15 @@ func (a *App) InjectUsers(gr *gin.Engine) {
 
 	words.POST("/registration", a.createUser)     // OK
 	words.GET("/me", a.authorizeRequest, a.getMe) // OK
+	words.GET("/streak", a.authorizeRequest, a.getStreak)
 
 	words.POST("/login", a.loginUser) // OK
 
 	settings := words.Group("/settings", a.authorizeRequest)
 
 	settings.PUT("/language", a.updateUserLanguage)
+	settings.PUT("/timezone", a.updateUserTimezone)
+	settings.PUT("/dailyGoal", a.updateUserDailyGoal)
 }
 
 type createUserInp struct {
@@ -138,11 +146,60 @@ func (a *App) getMe(ctx *gin.Context) {
 		return
 	}
 
+	// profile is returned even if streak can't be calculated, streak endpoint reports the error
+	userStreak, err := a.getUserStreak(user)
+	if err != nil {
+		fmt.Printf("failed to calculate streak of user %d: %s\n", user.Id, err.Error())
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"user":   user,
+		"streak": userStreak,
+	})
+}
+
+func (a *App) getStreak(ctx *gin.Context) {
+	user := a.getContextUser(ctx)
+
+	userStreak, err := a.getUserStreak(user)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	ctx.JSON(http.StatusOK, map[string]interface{}{
-		"user": user,
+		"streak": userStreak,
 	})
 }
 
+// getUserStreak calculates streaks in user timezone over the last streakDays days,
+// longest streak is the longest one of this period
+func (a *App) getUserStreak(user *models.User) (*models.Streak, error) {
+	location := user.Settings.Location()
+
+	to := endOfDay(startOfDay(time.Now(), location))
+
+	from := startOfDay(to.AddDate(0, 0, -streakDays), location)
+	if createdAt := startOfDay(user.CreatedAt, location); createdAt.After(from) {
+		from = createdAt
+	}
+
+	activity, err := a.getUserActivity(user.Id, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var dailyGoal uint64
+	if user.Settings != nil {
+		dailyGoal = user.Settings.DailyGoal
+	}
+
+	userStreak := streak.Calculate(activity, dailyGoal)
+	userStreak.Timezone = location.String()
+
+	return &userStreak, nil
+}
+
 type updateUserLanguageInp struct {
 	Language string `json:"language"`
 }
@@ -168,3 +225,66 @@ func (a *App) updateUserLanguage(ctx *gin.Context) {
 		"language": input.Language,
 	})
 }
+
+type updateUserTimezoneInp struct {
+	Timezone string `json:"timezone"`
+}
+
+func (a *App) updateUserTimezone(ctx *gin.Context) {
+	var input updateUserTimezoneInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	location, err := time.LoadLocation(input.Timezone)
+	if err != nil || input.Timezone == "" {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("unknown timezone").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	err = a.userRepo.UpdateUserTimezone(location.String(), user.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":  "success",
+		"timezone": location.String(),
+	})
+}
+
+type updateUserDailyGoalInp struct {
+	DailyGoal uint64 `json:"dailyGoal"`
+}
+
+func (a *App) updateUserDailyGoal(ctx *gin.Context) {
+	var input updateUserDailyGoalInp
+	err := ctx.BindJSON(&input)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if input.DailyGoal == 0 {
+		newErrorResponse(ctx, http.StatusBadRequest, errors.New("daily goal must be greater than zero").Error())
+		return
+	}
+
+	user := a.getContextUser(ctx)
+
+	err = a.userRepo.UpdateUserDailyGoal(input.DailyGoal, user.Id)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"message":   "success",
+		"dailyGoal": input.DailyGoal,
+	})
+}
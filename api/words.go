@@ -4,8 +4,10 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 	"vacabulary/models"
-	"vacabulary/repositories/elastic"
+	"vacabulary/pkg/translator"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,20 +17,27 @@ func (a *App) InjectWords(gr *gin.Engine) {
 	words.POST("", a.createWord)       // OK
 	words.POST("/bulk", a.createWords) // OK
 
-	words.GET(":id/collection/:collectionId", a.idParam("collectionId"), a.getWord)       // OK
-	words.DELETE(":id/collection/:collectionId", a.idParam("collectionId"), a.deleteWord) // OK
-	words.GET("/collection/:collectionId", a.idParam("collectionId"), a.getAllWords)      // OK
-	words.PUT(":id/collection/:collectionId", a.idParam("collectionId"), a.updateWord)    // OK
+	words.GET(":id/collection/:collectionId", a.idParam("collectionId"), a.collectionAccess("collectionId", collectionRead), a.getWord)       // OK
+	words.DELETE(":id/collection/:collectionId", a.idParam("collectionId"), a.collectionAccess("collectionId", collectionEdit), a.deleteWord) // OK
+	words.GET("/collection/:collectionId", a.idParam("collectionId"), a.collectionAccess("collectionId", collectionRead), a.getAllWords)      // OK
+	words.PUT(":id/collection/:collectionId", a.idParam("collectionId"), a.collectionAccess("collectionId", collectionEdit), a.updateWord)    // OK
 
 	words.POST("/translate", a.translateWord)
 }
 
 type createWordInp struct {
-	Word         string `json:"word"`
-	Translation  string `json:"translation"`
-	PartOfSpeech string `json:"partOfSpeech"`
-	Scentance    string `json:"scentance"`
-	CollectionId uint64 `json:"collectionId"`
+	Word          string   `json:"word"`
+	Translation   string   `json:"translation"`
+	Translations  []string `json:"translations"`
+	PartOfSpeech  string   `json:"partOfSpeech"`
+	Scentance     string   `json:"scentance"`
+	Sentences     []string `json:"sentences"`
+	Synonyms      []string `json:"synonyms"`
+	Antonyms      []string `json:"antonyms"`
+	Note          string   `json:"note"`
+	Transcription string   `json:"transcription"`
+	Tags          []string `json:"tags"`
+	CollectionId  uint64   `json:"collectionId"`
 }
 
 func (a *App) createWord(ctx *gin.Context) {
@@ -39,10 +48,13 @@ func (a *App) createWord(ctx *gin.Context) {
 		return
 	}
 
-	user := a.getContextUser(ctx)
+	wordsCtx, ok := a.collectionWordsCtx(ctx, input.CollectionId, collectionEdit)
+	if !ok {
+		return
+	}
 
 	// check: such origin already esists in selected collection or not
-	word, err := a.wordRepo.Get(input.Word, elastic.CollectionWordsOperationCtx{CollectionId: input.CollectionId, UserId: user.Id})
+	word, err := a.wordRepo.Get(input.Word, wordsCtx)
 	if err != nil {
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
@@ -55,12 +67,19 @@ func (a *App) createWord(ctx *gin.Context) {
 
 	// create word in elastic too
 	err = a.wordRepo.Create(models.Word{
-		Word:         input.Word,
-		Translation:  input.Translation,
-		PartOfSpeech: input.PartOfSpeech,
-		Scentance:    input.Scentance,
-		CollectionId: input.CollectionId,
-	}, elastic.CollectionWordsOperationCtx{CollectionId: input.CollectionId, UserId: user.Id})
+		Word:          input.Word,
+		Translation:   input.Translation,
+		Translations:  input.Translations,
+		PartOfSpeech:  input.PartOfSpeech,
+		Scentance:     input.Scentance,
+		Sentences:     input.Sentences,
+		Synonyms:      input.Synonyms,
+		Antonyms:      input.Antonyms,
+		Note:          input.Note,
+		Transcription: input.Transcription,
+		Tags:          input.Tags,
+		CollectionId:  input.CollectionId,
+	}, wordsCtx)
 	if err != nil {
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
@@ -77,12 +96,6 @@ type getAllWordsResponse struct {
 }
 
 func (a *App) getAllWords(ctx *gin.Context) {
-	collectionId := ctx.GetUint64("collectionId")
-	if collectionId == 0 {
-		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get collection id").Error())
-		return
-	}
-
 	// get pagination params
 	size, page, err := getPaginationParams(ctx)
 	if err != nil {
@@ -90,9 +103,13 @@ func (a *App) getAllWords(ctx *gin.Context) {
 		return
 	}
 
-	user := a.getContextUser(ctx)
+	filter, err := getWordsFilterParams(ctx)
+	if err != nil {
+		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	words, totalWords, err := a.wordRepo.GetAll(uint64(size), uint64(page), elastic.CollectionWordsOperationCtx{CollectionId: collectionId, UserId: user.Id})
+	words, totalWords, err := a.wordRepo.GetAllFiltered(uint64(size), uint64(page), filter, contextWordsCtx(ctx))
 	if err != nil {
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
@@ -120,9 +137,49 @@ func getPaginationParams(ctx *gin.Context) (uint64, uint64, error) {
 		return 0, 0, err
 	}
 
+	if size < 1 || page < 1 {
+		return 0, 0, errors.New("size and page must be positive")
+	}
+
 	return uint64(size), uint64(page), nil
 }
 
+func getWordsFilterParams(ctx *gin.Context) (models.WordsFilter, error) {
+	filter := models.WordsFilter{}
+
+	masteryStr := ctx.Query("mastery")
+	if masteryStr != "" {
+		for _, m := range strings.Split(masteryStr, ",") {
+			level := models.MasteryLevel(m)
+			if !level.IsValid() {
+				return filter, errors.New("mastery must be one of new, learning, known, mastered")
+			}
+			filter.Mastery = append(filter.Mastery, level)
+		}
+	}
+
+	tagsStr := ctx.Query("tags")
+	if tagsStr != "" {
+		for _, t := range strings.Split(tagsStr, ",") {
+			if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+				filter.Tags = append(filter.Tags, t)
+			}
+		}
+	}
+
+	filter.SortBy = ctx.Query("sortBy")
+	if filter.SortBy != "" && filter.SortBy != models.WordsSortByCreatedAt && filter.SortBy != models.WordsSortByMastery {
+		return filter, errors.New("sortBy must be one of createdAt, mastery")
+	}
+
+	filter.SortOrder = ctx.Query("sortOrder")
+	if filter.SortOrder != "" && filter.SortOrder != models.SortOrderAsc && filter.SortOrder != models.SortOrderDesc {
+		return filter, errors.New("sortOrder must be one of asc, desc")
+	}
+
+	return filter, nil
+}
+
 type getWordResponse struct {
 	Word models.Word `json:"word"`
 }
@@ -135,22 +192,8 @@ func (a *App) getWord(ctx *gin.Context) {
 		return
 	}
 
-	collectionId := ctx.GetUint64("collectionId")
-	if collectionId == 0 {
-		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get collection id").Error())
-		return
-	}
-
-	user := a.getContextUser(ctx)
-
-	word, err := a.wordRepo.GetById(id, elastic.CollectionWordsOperationCtx{CollectionId: collectionId, UserId: user.Id})
-	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	if word == nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, errors.New("word with such origin not found").Error())
+	word, ok := a.getCollectionWord(ctx, id)
+	if !ok {
 		return
 	}
 
@@ -160,13 +203,22 @@ func (a *App) getWord(ctx *gin.Context) {
 
 }
 
+// updateWordInp lists, note and transcription which are missing in request keep existing values,
+// so clients which send only word, translation, part of speech and scentance don't erase them
 type updateWordInp struct {
-	Id           string `json:"id"`
-	Word         string `json:"word"`
-	Translation  string `json:"translation"`
-	PartOfSpeech string `json:"partOfSpeech"`
-	Scentance    string `json:"scentance"`
-	CollectionId uint64 `json:"collectionId"`
+	Id            string   `json:"id"`
+	Word          string   `json:"word"`
+	Translation   string   `json:"translation"`
+	Translations  []string `json:"translations"`
+	PartOfSpeech  string   `json:"partOfSpeech"`
+	Scentance     string   `json:"scentance"`
+	Sentences     []string `json:"sentences"`
+	Synonyms      []string `json:"synonyms"`
+	Antonyms      []string `json:"antonyms"`
+	Note          *string  `json:"note"`
+	Transcription *string  `json:"transcription"`
+	Tags          []string `json:"tags"`
+	CollectionId  uint64   `json:"collectionId"`
 }
 
 func (a *App) updateWord(ctx *gin.Context) {
@@ -183,41 +235,86 @@ func (a *App) updateWord(ctx *gin.Context) {
 		return
 	}
 
-	collectionId := ctx.GetUint64("collectionId")
-	if collectionId == 0 {
-		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get collection id").Error())
+	word, ok := a.getCollectionWord(ctx, id)
+	if !ok {
 		return
 	}
 
-	user := a.getContextUser(ctx)
+	updatedWord := models.Word{
+		Id:            id,
+		Word:          input.Word,
+		Translation:   input.Translation,
+		Translations:  replaceMainVariant(word.Translation, input.Translation, word.Translations),
+		PartOfSpeech:  input.PartOfSpeech,
+		Scentance:     input.Scentance,
+		Sentences:     replaceMainVariant(word.Scentance, input.Scentance, word.Sentences),
+		Synonyms:      word.Synonyms,
+		Antonyms:      word.Antonyms,
+		Note:          word.Note,
+		Transcription: word.Transcription,
+		Tags:          word.Tags,
+		CreatedAt:     word.CreatedAt,
+		CollectionId:  word.CollectionId,
+	}
 
-	word, err := a.wordRepo.GetById(id, elastic.CollectionWordsOperationCtx{CollectionId: collectionId, UserId: user.Id})
-	if err != nil {
-		newErrorResponse(ctx, http.StatusBadRequest, err.Error())
-		return
+	if input.Translations != nil {
+		updatedWord.Translations = input.Translations
+	}
+
+	if input.Sentences != nil {
+		updatedWord.Sentences = input.Sentences
+	}
+
+	if input.Synonyms != nil {
+		updatedWord.Synonyms = input.Synonyms
+	}
+
+	if input.Antonyms != nil {
+		updatedWord.Antonyms = input.Antonyms
+	}
+
+	if input.Note != nil {
+		updatedWord.Note = *input.Note
+	}
+
+	if input.Transcription != nil {
+		updatedWord.Transcription = *input.Transcription
+	}
+
+	if input.Tags != nil {
+		updatedWord.Tags = input.Tags
 	}
 
 	// create word in elastic too
-	err = a.wordRepo.Update(models.Word{
-		Id:           id,
-		Word:         input.Word,
-		Translation:  input.Translation,
-		PartOfSpeech: input.PartOfSpeech,
-		Scentance:    input.Scentance,
-		CreatedAt:    word.CreatedAt,
-		CollectionId: word.CollectionId,
-	}, elastic.CollectionWordsOperationCtx{CollectionId: collectionId, UserId: user.Id})
+	err = a.wordRepo.Update(updatedWord, contextWordsCtx(ctx))
 	if err != nil {
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	user := a.getContextUser(ctx)
+	collection := getContextCollection(ctx)
+	a.recordWordHistory(*word, updatedWord, collection.OwnerId, user.Id, models.WordHistoryActionUpdate, nil)
+
 	ctx.JSON(http.StatusOK, map[string]interface{}{
 		"message": "success update",
 	})
 
 }
 
+// replaceMainVariant replaces previous main value in existing variants with the new one,
+// other variants are kept
+func replaceMainVariant(previous, main string, variants []string) []string {
+	result := []string{main}
+	for _, v := range variants {
+		if v != previous && v != main {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
 func (a *App) deleteWord(ctx *gin.Context) {
 	id := ctx.Param("id")
 	if id == "" {
@@ -225,15 +322,13 @@ func (a *App) deleteWord(ctx *gin.Context) {
 		return
 	}
 
-	collectionId := ctx.GetUint64("collectionId")
-	if collectionId == 0 {
-		newErrorResponse(ctx, http.StatusBadRequest, errors.New("can not get collection id").Error())
+	_, ok := a.getCollectionWord(ctx, id)
+	if !ok {
 		return
 	}
 
-	user := a.getContextUser(ctx)
-
-	err := a.wordRepo.DeleteById(id, elastic.CollectionWordsOperationCtx{CollectionId: collectionId, UserId: user.Id})
+	// word is moved to trash and can be restored
+	err := a.wordRepo.SoftDeleteById(id, time.Now(), contextWordsCtx(ctx))
 	if err != nil {
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
@@ -246,10 +341,17 @@ func (a *App) deleteWord(ctx *gin.Context) {
 }
 
 type createWordsWordInp struct {
-	Word         string `json:"word"`
-	Translation  string `json:"translation"`
-	PartOfSpeech string `json:"partOfSpeech"`
-	Scentance    string `json:"scentance"`
+	Word          string   `json:"word"`
+	Translation   string   `json:"translation"`
+	Translations  []string `json:"translations"`
+	PartOfSpeech  string   `json:"partOfSpeech"`
+	Scentance     string   `json:"scentance"`
+	Sentences     []string `json:"sentences"`
+	Synonyms      []string `json:"synonyms"`
+	Antonyms      []string `json:"antonyms"`
+	Note          string   `json:"note"`
+	Transcription string   `json:"transcription"`
+	Tags          []string `json:"tags"`
 }
 
 type createWordsInp struct {
@@ -270,10 +372,13 @@ func (a *App) createWords(ctx *gin.Context) {
 		wordsWord = append(wordsWord, w.Word)
 	}
 
-	user := a.getContextUser(ctx)
+	wordsCtx, ok := a.collectionWordsCtx(ctx, input.CollectionId, collectionEdit)
+	if !ok {
+		return
+	}
 
 	// check: such words already esists or not
-	words, err := a.wordRepo.GetByWords(wordsWord, elastic.CollectionWordsOperationCtx{CollectionId: uint64(input.CollectionId), UserId: user.Id})
+	words, err := a.wordRepo.GetByWords(wordsWord, wordsCtx)
 	if err != nil {
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
@@ -287,16 +392,23 @@ func (a *App) createWords(ctx *gin.Context) {
 	words = []models.Word{}
 	for _, w := range input.Words {
 		words = append(words, models.Word{
-			Word:         w.Word,
-			Translation:  w.Translation,
-			PartOfSpeech: w.PartOfSpeech,
-			Scentance:    w.Scentance,
-			CollectionId: input.CollectionId,
+			Word:          w.Word,
+			Translation:   w.Translation,
+			Translations:  w.Translations,
+			PartOfSpeech:  w.PartOfSpeech,
+			Scentance:     w.Scentance,
+			Sentences:     w.Sentences,
+			Synonyms:      w.Synonyms,
+			Antonyms:      w.Antonyms,
+			Note:          w.Note,
+			Transcription: w.Transcription,
+			Tags:          w.Tags,
+			CollectionId:  input.CollectionId,
 		})
 	}
 
 	// create words in elastic too
-	err = a.wordRepo.BulkCreate(words, elastic.CollectionWordsOperationCtx{CollectionId: uint64(input.CollectionId), UserId: user.Id})
+	err = a.wordRepo.BulkCreate(words, wordsCtx)
 	if err != nil {
 		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 		return
@@ -327,9 +439,16 @@ func (a *App) translateWord(ctx *gin.Context) {
 		return
 	}
 
-	translatedWord, err := a.translatorManager.TranslateWord(input.Word, langFrom, langTo)
+	translatedWord, err := a.translator.TranslateWord(input.Word, langFrom, langTo)
 	if err != nil {
-		newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		switch {
+		case errors.Is(err, translator.ErrUnsupportedLanguage):
+			newErrorResponse(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, translator.ErrNoTranslation):
+			newErrorResponse(ctx, http.StatusNotFound, err.Error())
+		default:
+			newErrorResponse(ctx, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
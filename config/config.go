@@ -4,16 +4,21 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type AppConfig struct {
-	Elastic  ElasticConfig  `yaml:"elastic"`
-	Postgres PostgresConfig `yaml:"postgres"`
-	Salt     string         `yaml:"salt"`
-	AWS      AWSConfig      `yaml:"aws"`
-	Hasher   Hasher         `yaml:"hasher"`
+	Elastic    ElasticConfig    `yaml:"elastic"`
+	Postgres   PostgresConfig   `yaml:"postgres"`
+	Salt       string           `yaml:"salt"`
+	AWS        AWSConfig        `yaml:"aws"`
+	Hasher     Hasher           `yaml:"hasher"`
+	Trash      TrashConfig      `yaml:"trash"`
+	Storage    StorageConfig    `yaml:"storage"`
+	Translator TranslatorConfig `yaml:"translator"`
 }
 
 type ElasticConfig struct {
@@ -34,6 +39,104 @@ type Hasher struct {
 	Cost int `yaml:"cost"`
 }
 
+// TrashConfig sets how many days deleted words and collections are kept before purge
+type TrashConfig struct {
+	RetentionDays int `yaml:"retentionDays"`
+}
+
+const defaultTrashRetentionDays = 30
+
+// Retention returns retention period, default one is used when it is not configured
+func (c TrashConfig) Retention() time.Duration {
+	days := c.RetentionDays
+	if days <= 0 {
+		days = defaultTrashRetentionDays
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// StorageConfig selects where exported files are kept: s3 or local directory.
+// Endpoint is set for s3 compatible storage like MinIO, Dir, BaseUrl and SigningSecret of download urls
+// are used by local storage only.
+type StorageConfig struct {
+	Type          string `yaml:"type"`
+	Bucket        string `yaml:"bucket"`
+	Endpoint      string `yaml:"endpoint"`
+	Dir           string `yaml:"dir"`
+	BaseUrl       string `yaml:"baseUrl"`
+	UrlTTLMinutes int    `yaml:"urlTtlMinutes"`
+	SigningSecret string `yaml:"signingSecret"`
+}
+
+const (
+	StorageTypeS3    = "s3"
+	StorageTypeLocal = "local"
+
+	defaultStorageBucket        = "collections-words"
+	defaultStorageUrlTTLMinutes = 15
+)
+
+// GetBucket returns configured bucket or the one used before storage was configurable
+func (c StorageConfig) GetBucket() string {
+	if c.Bucket == "" {
+		return defaultStorageBucket
+	}
+
+	return c.Bucket
+}
+
+// UrlTTL returns how long download url is valid
+func (c StorageConfig) UrlTTL() time.Duration {
+	minutes := c.UrlTTLMinutes
+	if minutes <= 0 {
+		minutes = defaultStorageUrlTTLMinutes
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+// TranslatorConfig sets translation providers in order they are tried, the next one is used
+// when previous fails or doesn't know the word. AWS Translate is used when providers are empty.
+type TranslatorConfig struct {
+	Providers      []string             `yaml:"providers"`
+	LibreTranslate LibreTranslateConfig `yaml:"libreTranslate"`
+	DictionaryPath string               `yaml:"dictionaryPath"`
+	TimeoutSeconds int                  `yaml:"timeoutSeconds"`
+}
+
+type LibreTranslateConfig struct {
+	Url    string `yaml:"url"`
+	ApiKey string `yaml:"apiKey"`
+}
+
+const (
+	TranslatorProviderAWS            = "aws"
+	TranslatorProviderLibreTranslate = "libretranslate"
+	TranslatorProviderDictionary     = "dictionary"
+
+	defaultTranslatorTimeoutSeconds = 10
+)
+
+// GetProviders returns configured providers or AWS Translate used before providers were configurable
+func (c TranslatorConfig) GetProviders() []string {
+	if len(c.Providers) == 0 {
+		return []string{TranslatorProviderAWS}
+	}
+
+	return c.Providers
+}
+
+// Timeout returns how long http providers wait for translation
+func (c TranslatorConfig) Timeout() time.Duration {
+	seconds := c.TimeoutSeconds
+	if seconds <= 0 {
+		seconds = defaultTranslatorTimeoutSeconds
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
 type AWSConfig struct {
 	Region   string `yaml:"region"`
 	AccessId string `yaml:"accessId"`
@@ -152,6 +255,51 @@ func (p *Prod) GetEnvVariables() error {
 	}
 	Config.AWS.Region = data
 
+	// trash retention is optional, default one is used without it
+	data, ok = os.LookupEnv("TRASH_RETENTION_DAYS")
+	if ok {
+		dataN, err = strconv.Atoi(data)
+		if err != nil {
+			fmt.Println("can`t parse env variable")
+		}
+		Config.Trash.RetentionDays = dataN
+	}
+
+	// storage of exported files is optional, s3 with default bucket is used without it
+	Config.Storage.Type = os.Getenv("STORAGE_TYPE")
+	Config.Storage.Bucket = os.Getenv("STORAGE_BUCKET")
+	Config.Storage.Endpoint = os.Getenv("STORAGE_ENDPOINT")
+	Config.Storage.Dir = os.Getenv("STORAGE_DIR")
+	Config.Storage.BaseUrl = os.Getenv("STORAGE_BASE_URL")
+	Config.Storage.SigningSecret = os.Getenv("STORAGE_SIGNING_SECRET")
+
+	data, ok = os.LookupEnv("STORAGE_URL_TTL_MINUTES")
+	if ok {
+		dataN, err = strconv.Atoi(data)
+		if err != nil {
+			fmt.Println("can`t parse env variable")
+		}
+		Config.Storage.UrlTTLMinutes = dataN
+	}
+
+	// translator providers are optional, aws is used without them
+	data, ok = os.LookupEnv("TRANSLATOR_PROVIDERS")
+	if ok && data != "" {
+		Config.Translator.Providers = strings.Split(data, ",")
+	}
+	Config.Translator.LibreTranslate.Url = os.Getenv("LIBRETRANSLATE_URL")
+	Config.Translator.LibreTranslate.ApiKey = os.Getenv("LIBRETRANSLATE_API_KEY")
+	Config.Translator.DictionaryPath = os.Getenv("TRANSLATOR_DICTIONARY_PATH")
+
+	data, ok = os.LookupEnv("TRANSLATOR_TIMEOUT_SECONDS")
+	if ok {
+		dataN, err = strconv.Atoi(data)
+		if err != nil {
+			fmt.Println("can`t parse env variable")
+		}
+		Config.Translator.TimeoutSeconds = dataN
+	}
+
 	return nil
 }
 
@@ -69,14 +69,71 @@ func NewCollectionWordsIndex(ctx CollectionWordsIndexContext) (*CollectionWordsI
 				"translation":{
 					"type":"text"
 				},
-				"part_of_speech":{
+				"translations":{
 					"type":"text"
 				},
+				"part_of_speech":{
+					"type":"text",
+					"fields":{
+						"keyword":{
+							"type":"keyword"
+						}
+					}
+				},
 				"scentance":{
 					"type":"text"
 				},
+				"sentences":{
+					"type":"text"
+				},
+				"synonyms":{
+					"type":"text"
+				},
+				"antonyms":{
+					"type":"text"
+				},
+				"note":{
+					"type":"text"
+				},
+				"transcription":{
+					"type":"keyword"
+				},
+				"tags":{
+					"type":"keyword"
+				},
 				"created_at":{
 					"type":"date"
+				},
+				"deleted_at":{
+					"type":"date"
+				},
+				"collection_deleted_at":{
+					"type":"date"
+				},
+				"progress":{
+					"properties":{
+						"ease_factor":{
+							"type":"float"
+						},
+						"interval":{
+							"type":"integer"
+						},
+						"repetitions":{
+							"type":"integer"
+						},
+						"box":{
+							"type":"integer"
+						},
+						"mastery":{
+							"type":"keyword"
+						},
+						"due_date":{
+							"type":"date"
+						},
+						"last_reviewed_at":{
+							"type":"date"
+						}
+					}
 				}
 			}
 		}
@@ -2,8 +2,13 @@ package elastic
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"vacabulary/models"
+	"vacabulary/pkg/scheduler"
+
+	"github.com/olivere/elastic/v7"
 )
 
 func (ec *ElasticClient) CreateUserWordsIndices(userId uint64) error {
@@ -20,6 +25,37 @@ func (ec *ElasticClient) CreateUserWordsIndices(userId uint64) error {
 	return nil
 }
 
+// UpdateUserWordsIndices creates missing user indices and
+// applies new fields of the mapping to already existing ones
+func (ec *ElasticClient) UpdateUserWordsIndices(userId uint64) error {
+	collectionWordsIndex, err := NewCollectionWordsIndex(CollectionWordsIndexContext{UserID: userId})
+	if err != nil {
+		return err
+	}
+
+	err = ec.createIndicesIfNotExists(collectionWordsIndex)
+	if err != nil {
+		return err
+	}
+
+	err = ec.updateIndicesMapping(collectionWordsIndex)
+	if err != nil {
+		return err
+	}
+
+	err = ec.reindexMissingSubField(collectionWordsIndex.GetName(), "part_of_speech", "part_of_speech.keyword")
+	if err != nil {
+		return err
+	}
+
+	err = ec.fillMissingMastery(collectionWordsIndex.GetName())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (ec *ElasticClient) CreateCollectionAliases(userId uint64, collectionId uint64) error {
 	collectionWordsIndex, err := NewCollectionWordsIndex(CollectionWordsIndexContext{UserID: userId})
 	if err != nil {
@@ -39,6 +75,22 @@ func (ec *ElasticClient) CreateCollectionAliases(userId uint64, collectionId uin
 	return nil
 }
 
+// DeleteCollectionAliases removes collection alias from user index,
+// returns false if alias already not exists
+func (ec *ElasticClient) DeleteCollectionAliases(userId uint64, collectionId uint64) (bool, error) {
+	collectionWordsIndex, err := NewCollectionWordsIndex(CollectionWordsIndexContext{UserID: userId})
+	if err != nil {
+		return false, err
+	}
+
+	collectionWordsAlias, err := NewCollectionWordsIndex(CollectionWordsIndexContext{UserID: userId, CollectionID: collectionId})
+	if err != nil {
+		return false, err
+	}
+
+	return ec.deleteAliasIfExists(collectionWordsIndex.GetName(), collectionWordsAlias.GetName())
+}
+
 func (ec *ElasticClient) createIndicesIfNotExists(indices ...CollectionWordsIndexInterface) error {
 	client, err := ec.GetConnection()
 	if err != nil {
@@ -70,6 +122,94 @@ func (ec *ElasticClient) createIndicesIfNotExists(indices ...CollectionWordsInde
 	return nil
 }
 
+func (ec *ElasticClient) updateIndicesMapping(indices ...CollectionWordsIndexInterface) error {
+	client, err := ec.GetConnection()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	for _, index := range indices {
+		var indexBody struct {
+			Mappings map[string]interface{} `json:"mappings"`
+		}
+
+		err = json.Unmarshal([]byte(index.GetMapping()), &indexBody)
+		if err != nil {
+			return err
+		}
+
+		result, err := client.PutMapping().Index(index.GetName()).BodyJson(indexBody.Mappings).Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !result.Acknowledged {
+			return errors.New("index mapping was not acknowledged")
+		}
+	}
+
+	return nil
+}
+
+// reindexMissingSubField updates documents in place, so multi-field
+// added to the mapping after they were indexed become searchable
+func (ec *ElasticClient) reindexMissingSubField(index, field, subField string) error {
+	client, err := ec.GetConnection()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	query := elastic.NewBoolQuery()
+	query.Must(elastic.NewExistsQuery(field))
+	query.MustNot(elastic.NewExistsQuery(subField))
+
+	_, err = client.UpdateByQuery(index).Query(query).ProceedOnVersionConflict().Refresh("true").Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// fillMissingMastery sets mastery level for words reviewed before
+// levels were introduced, thresholds are the same as in scheduler.MasteryLevel
+func (ec *ElasticClient) fillMissingMastery(index string) error {
+	client, err := ec.GetConnection()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	query := elastic.NewBoolQuery()
+	query.Must(elastic.NewExistsQuery("progress.last_reviewed_at"))
+	query.MustNot(elastic.NewExistsQuery("progress.mastery"))
+
+	script := elastic.NewScript(`
+		def interval = ctx._source.progress.interval;
+		if (interval >= params.masteredInterval) {
+			ctx._source.progress.mastery = params.mastered;
+		} else if (interval >= params.knownInterval) {
+			ctx._source.progress.mastery = params.known;
+		} else {
+			ctx._source.progress.mastery = params.learning;
+		}`).Params(map[string]interface{}{
+		"masteredInterval": scheduler.MasteredInterval,
+		"knownInterval":    scheduler.KnownInterval,
+		"mastered":         models.MasteryLevelMastered,
+		"known":            models.MasteryLevelKnown,
+		"learning":         models.MasteryLevelLearning,
+	})
+
+	_, err = client.UpdateByQuery(index).Query(query).Script(script).ProceedOnVersionConflict().Refresh("true").Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (ec *ElasticClient) createAliacesIfNotExists(index string, aliases ...CollectionWordsIndexInterface) error {
 	client, err := ec.GetConnection()
 	if err != nil {
@@ -110,3 +250,34 @@ func (ec *ElasticClient) createAliacesIfNotExists(index string, aliases ...Colle
 
 	return nil
 }
+
+func (ec *ElasticClient) deleteAliasIfExists(index string, aliasName string) (bool, error) {
+	client, err := ec.GetConnection()
+	if err != nil {
+		return false, err
+	}
+	ctx := context.Background()
+
+	aliasesResult, err := client.Aliases().Index(index).Do(ctx)
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if !aliasesResult.Indices[index].HasAlias(aliasName) {
+		return false, nil
+	}
+
+	response, err := client.Alias().Remove(index, aliasName).Do(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if !response.Acknowledged {
+		return false, fmt.Errorf("removal of alias %s from index %s was not acknowledged", aliasName, index)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,26 @@
+package elastic
+
+import "fmt"
+
+// IndicesVersion is increased when mapping of user indices or migration of their documents
+// is changed, indices of existing users are migrated once for every version
+const IndicesVersion = 2
+
+// MigrateIndices updates indices of the users, error is returned when any of them failed,
+// so the version is not marked as applied and migration is repeated on the next start
+func (ec *ElasticClient) MigrateIndices(userIds []uint64) error {
+	var failed int
+	for _, userId := range userIds {
+		err := ec.UpdateUserWordsIndices(userId)
+		if err != nil {
+			fmt.Printf("failed to migrate words index of user %v: %s\n", userId, err.Error())
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to migrate %d of %d words indices", failed, len(userIds))
+	}
+
+	return nil
+}
@@ -9,9 +9,11 @@ require (
 	github.com/golang-jwt/jwt v3.2.2+incompatible
 	github.com/golang-migrate/migrate/v4 v4.15.2
 	github.com/johnfercher/maroto v0.31.0
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/olivere/elastic/v7 v7.0.32
 	github.com/spf13/viper v1.14.0
 	golang.org/x/crypto v0.7.0
+	golang.org/x/text v0.8.0
 )
 
 require (
@@ -58,7 +60,6 @@ require (
 	go.uber.org/atomic v1.9.0 // indirect
 	golang.org/x/net v0.8.0 // indirect
 	golang.org/x/sys v0.6.0 // indirect
-	golang.org/x/text v0.8.0 // indirect
 	google.golang.org/protobuf v1.28.1 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
@@ -3,13 +3,16 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"time"
+	_ "time/tzdata"
 	"vacabulary/api"
 	"vacabulary/config"
 	"vacabulary/db/elastic"
 	"vacabulary/db/postgres"
 
 	"vacabulary/pkg/hasher"
-	"vacabulary/pkg/s3"
+	"vacabulary/pkg/quiz"
+	"vacabulary/pkg/storage"
 	"vacabulary/pkg/token"
 	"vacabulary/pkg/translator"
 
@@ -21,6 +24,13 @@ import (
 	"vacabulary/server"
 )
 
+const (
+	collectionDeletionsRetryInterval = 5 * time.Minute
+	trashPurgeInterval               = time.Hour
+	exportJobsInterval               = 30 * time.Second
+	translationJobsInterval          = 30 * time.Second
+)
+
 func main() {
 	cfg := config.Config
 
@@ -31,13 +41,29 @@ func main() {
 	pgClient := postgres.NewPostgres(cfg.Postgres)
 
 	tokenService := token.NewTokenService(cfg.Salt)
-	translatorManager := translator.NewTranslatorManager(cfg.AWS)
-	s3Manager := s3.NewS3Manager(cfg.AWS)
+	wordTranslator, err := translator.NewTranslator(cfg.Translator, cfg.AWS)
+	if err != nil {
+		panic(err)
+	}
+	fileStorage, err := storage.NewStorage(cfg.Storage, cfg.AWS)
+	if err != nil {
+		panic(err)
+	}
 	hasher := hasher.NewHasher(cfg.Hasher.Cost)
+	quizGenerator := quiz.NewQuizGenerator()
 
 	elWordsRepo := elrepositories.NewCollectionWordsRepo(elClient.Client)
 	usersRepo := postgresRepo.NewUsersRepo(pgClient)
 	collectionsRepo := postgresRepo.NewCollectionsRepo(pgClient)
+	studySessionsRepo := postgresRepo.NewStudySessionsRepo(pgClient)
+	collectionMembersRepo := postgresRepo.NewCollectionMembersRepo(pgClient)
+	collectionDeletionsRepo := postgresRepo.NewCollectionDeletionsRepo(pgClient)
+	wordHistoriesRepo := postgresRepo.NewWordHistoriesRepo(pgClient)
+	exportJobsRepo := postgresRepo.NewExportJobsRepo(pgClient)
+	translationJobsRepo := postgresRepo.NewTranslationJobsRepo(pgClient)
+
+	elasticMigrationsRepo := postgresRepo.NewElasticMigrationsRepo(pgClient)
+	migrateElasticIndices(elClient, usersRepo, elasticMigrationsRepo)
 
 	router := server.NewServer()
 
@@ -55,7 +81,7 @@ func main() {
 		c.Next()
 	})
 
-	app := api.NewApp(usersRepo, collectionsRepo, elWordsRepo, *tokenService, translatorManager, s3Manager, hasher)
+	app := api.NewApp(usersRepo, collectionsRepo, elWordsRepo, studySessionsRepo, collectionMembersRepo, collectionDeletionsRepo, wordHistoriesRepo, exportJobsRepo, translationJobsRepo, *tokenService, wordTranslator, fileStorage, hasher, quizGenerator)
 
 	router.GET("/", func(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK, "hello from api new")
@@ -63,5 +89,50 @@ func main() {
 
 	app.AttachEndpoints(router)
 
+	go app.RetryCollectionDeletions(collectionDeletionsRetryInterval)
+	go app.PurgeTrash(cfg.Trash.Retention(), trashPurgeInterval)
+	go app.RunExportJobs(exportJobsInterval)
+	go app.RunTranslationJobs(translationJobsInterval)
+
 	router.Run()
 }
+
+// migrateElasticIndices updates indices of existing users once for every version of indices,
+// indices of new users are created with the actual mapping
+func migrateElasticIndices(elClient *elastic.ElasticClient, usersRepo postgresRepo.Users, elasticMigrationsRepo postgresRepo.ElasticMigrations) {
+	applied, err := elasticMigrationsRepo.IsApplied(elastic.IndicesVersion)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	if applied {
+		fmt.Println("Elastic indices are up to date")
+		return
+	}
+
+	users, err := usersRepo.GetAll()
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	var userIds []uint64
+	for _, u := range users {
+		userIds = append(userIds, u.Id)
+	}
+
+	err = elClient.MigrateIndices(userIds)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	err = elasticMigrationsRepo.Create(elastic.IndicesVersion, time.Now())
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Printf("Elastic indices are migrated to version %d\n", elastic.IndicesVersion)
+}
@@ -0,0 +1,33 @@
+CREATE TABLE study_sessions(
+    id SERIAL PRIMARY KEY,
+    user_id int NOT NULL,
+    collection_id int NOT NULL,
+    mode text NOT NULL,
+    questions_count int NOT NULL DEFAULT 0,
+    correct_count int NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_collection
+        FOREIGN KEY(collection_id)
+            REFERENCES collections(id) ON DELETE CASCADE
+);
+
+CREATE TABLE study_session_answers(
+    id SERIAL PRIMARY KEY,
+    session_id int NOT NULL,
+    word_id text NOT NULL,
+    word text,
+    correct boolean NOT NULL,
+    answered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    CONSTRAINT fk_session
+        FOREIGN KEY(session_id)
+            REFERENCES study_sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX study_sessions_user_collection_idx ON study_sessions(user_id, collection_id);
+CREATE INDEX study_session_answers_session_idx ON study_session_answers(session_id);
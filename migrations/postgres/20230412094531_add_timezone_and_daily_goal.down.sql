@@ -0,0 +1,2 @@
+ALTER TABLE user_settings DROP COLUMN timezone;
+ALTER TABLE user_settings DROP COLUMN daily_goal;
@@ -0,0 +1,2 @@
+ALTER TABLE user_settings ADD COLUMN timezone text NOT NULL DEFAULT 'UTC';
+ALTER TABLE user_settings ADD COLUMN daily_goal int NOT NULL DEFAULT 10;
@@ -0,0 +1,2 @@
+ALTER TABLE collections DROP COLUMN scheduler;
+ALTER TABLE collections DROP COLUMN leitner_intervals;
@@ -0,0 +1,2 @@
+ALTER TABLE collections ADD COLUMN scheduler text NOT NULL DEFAULT 'sm2';
+ALTER TABLE collections ADD COLUMN leitner_intervals int[];
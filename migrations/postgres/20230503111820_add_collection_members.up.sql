@@ -0,0 +1,23 @@
+CREATE TABLE collection_members(
+    id SERIAL PRIMARY KEY,
+    collection_id int NOT NULL,
+    user_id int NOT NULL,
+    role text NOT NULL,
+    status text NOT NULL,
+    invited_by int,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT collection_members_unique UNIQUE(collection_id, user_id),
+    CONSTRAINT fk_collection
+        FOREIGN KEY(collection_id)
+            REFERENCES collections(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_invited_by
+        FOREIGN KEY(invited_by)
+            REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX collection_members_user_idx ON collection_members(user_id);
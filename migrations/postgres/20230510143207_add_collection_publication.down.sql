@@ -0,0 +1,5 @@
+DROP INDEX collections_public_idx;
+
+ALTER TABLE collections DROP COLUMN published_at;
+ALTER TABLE collections DROP COLUMN is_public;
+ALTER TABLE collections DROP COLUMN description;
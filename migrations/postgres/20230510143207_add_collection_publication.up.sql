@@ -0,0 +1,5 @@
+ALTER TABLE collections ADD COLUMN description text NOT NULL DEFAULT '';
+ALTER TABLE collections ADD COLUMN is_public boolean NOT NULL DEFAULT false;
+ALTER TABLE collections ADD COLUMN published_at timestamp;
+
+CREATE INDEX collections_public_idx ON collections (lang_from, lang_to) WHERE is_public;
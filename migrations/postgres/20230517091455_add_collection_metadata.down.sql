@@ -0,0 +1,6 @@
+ALTER TABLE collections DROP CONSTRAINT collections_owner_name_key;
+
+ALTER TABLE collections DROP COLUMN sort_order;
+ALTER TABLE collections DROP COLUMN archived;
+ALTER TABLE collections DROP COLUMN icon;
+ALTER TABLE collections DROP COLUMN color;
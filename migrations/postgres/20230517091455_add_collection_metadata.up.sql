@@ -0,0 +1,6 @@
+ALTER TABLE collections ADD COLUMN color text NOT NULL DEFAULT '';
+ALTER TABLE collections ADD COLUMN icon text NOT NULL DEFAULT '';
+ALTER TABLE collections ADD COLUMN archived boolean NOT NULL DEFAULT false;
+ALTER TABLE collections ADD COLUMN sort_order int NOT NULL DEFAULT 0;
+
+ALTER TABLE collections ADD CONSTRAINT collections_owner_name_key UNIQUE (owner_id, name);
@@ -0,0 +1,22 @@
+CREATE TABLE collection_deletions(
+    id SERIAL PRIMARY KEY,
+    collection_id int NOT NULL,
+    collection_name text,
+    owner_id int NOT NULL,
+    status text NOT NULL,
+    attempts int NOT NULL DEFAULT 0,
+    error text NOT NULL DEFAULT '',
+    members_removed int NOT NULL DEFAULT 0,
+    sessions_removed int NOT NULL DEFAULT 0,
+    words_removed int NOT NULL DEFAULT 0,
+    alias_removed boolean NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT fk_owner
+        FOREIGN KEY(owner_id)
+            REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX collection_deletions_owner_idx ON collection_deletions(owner_id);
+CREATE INDEX collection_deletions_status_idx ON collection_deletions(status);
@@ -0,0 +1,11 @@
+DROP INDEX collections_deleted_at_idx;
+DROP INDEX collections_owner_name_key;
+
+-- collections in trash are restored, names taken by other collections get id suffix
+UPDATE collections c SET name = c.name || ' (restored ' || c.id || ')'
+WHERE c.deleted_at IS NOT NULL AND EXISTS (
+    SELECT 1 FROM collections o WHERE o.owner_id = c.owner_id AND o.name = c.name AND o.id <> c.id
+);
+ALTER TABLE collections ADD CONSTRAINT collections_owner_name_key UNIQUE (owner_id, name);
+
+ALTER TABLE collections DROP COLUMN deleted_at;
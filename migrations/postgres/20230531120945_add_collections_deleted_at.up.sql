@@ -0,0 +1,7 @@
+ALTER TABLE collections ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- collection in trash keeps its name until it is purged or restored
+ALTER TABLE collections DROP CONSTRAINT collections_owner_name_key;
+CREATE UNIQUE INDEX collections_owner_name_key ON collections (owner_id, name) WHERE deleted_at IS NULL;
+
+CREATE INDEX collections_deleted_at_idx ON collections (deleted_at) WHERE deleted_at IS NOT NULL;
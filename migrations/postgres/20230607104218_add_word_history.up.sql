@@ -0,0 +1,21 @@
+CREATE TABLE word_history(
+    id SERIAL PRIMARY KEY,
+    word_id text NOT NULL,
+    collection_id int NOT NULL,
+    owner_id int NOT NULL,
+    actor_id int,
+    action text NOT NULL,
+    reverted_from int,
+    changes jsonb NOT NULL DEFAULT '[]',
+    snapshot jsonb NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    CONSTRAINT fk_collection
+        FOREIGN KEY(collection_id)
+            REFERENCES collections(id) ON DELETE CASCADE,
+    CONSTRAINT fk_actor
+        FOREIGN KEY(actor_id)
+            REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX word_history_word_idx ON word_history(word_id, created_at);
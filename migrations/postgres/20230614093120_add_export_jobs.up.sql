@@ -0,0 +1,25 @@
+CREATE TABLE export_jobs(
+    id SERIAL PRIMARY KEY,
+    collection_id int NOT NULL,
+    owner_id int NOT NULL,
+    user_id int NOT NULL,
+    format text NOT NULL,
+    options jsonb,
+    status text NOT NULL,
+    attempts int NOT NULL DEFAULT 0,
+    error text NOT NULL DEFAULT '',
+    file_key text NOT NULL DEFAULT '',
+    file_name text NOT NULL DEFAULT '',
+    file_size bigint NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX export_jobs_user_idx ON export_jobs(user_id);
+CREATE INDEX export_jobs_status_idx ON export_jobs(status, created_at);
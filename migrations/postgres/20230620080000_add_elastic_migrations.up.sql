@@ -0,0 +1,4 @@
+CREATE TABLE elastic_migrations(
+    version int PRIMARY KEY,
+    applied_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
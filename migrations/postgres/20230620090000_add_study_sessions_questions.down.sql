@@ -0,0 +1 @@
+ALTER TABLE study_sessions DROP COLUMN IF EXISTS questions;
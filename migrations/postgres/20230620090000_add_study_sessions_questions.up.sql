@@ -0,0 +1 @@
+ALTER TABLE study_sessions ADD COLUMN questions jsonb;
@@ -0,0 +1 @@
+ALTER TABLE export_jobs ADD COLUMN next_attempt_at TIMESTAMP WITH TIME ZONE;
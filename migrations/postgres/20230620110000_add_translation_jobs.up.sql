@@ -0,0 +1,21 @@
+CREATE TABLE translation_jobs(
+    id SERIAL PRIMARY KEY,
+    collection_id int NOT NULL,
+    owner_id int NOT NULL,
+    user_id int NOT NULL,
+    words text[] NOT NULL,
+    status text NOT NULL,
+    attempts int NOT NULL DEFAULT 0,
+    translated int NOT NULL DEFAULT 0,
+    error text NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX translation_jobs_status_idx ON translation_jobs(status, created_at);
@@ -0,0 +1,14 @@
+-- answers of reviews without session can't be kept, they are not removed silently
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM study_session_answers WHERE session_id IS NULL) THEN
+        RAISE EXCEPTION 'study_session_answers has answers without session';
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS study_session_answers_user_idx;
+ALTER TABLE study_session_answers DROP CONSTRAINT IF EXISTS fk_collection;
+ALTER TABLE study_session_answers DROP CONSTRAINT IF EXISTS fk_user;
+ALTER TABLE study_session_answers ALTER COLUMN session_id SET NOT NULL;
+ALTER TABLE study_session_answers DROP COLUMN IF EXISTS collection_id;
+ALTER TABLE study_session_answers DROP COLUMN IF EXISTS user_id;
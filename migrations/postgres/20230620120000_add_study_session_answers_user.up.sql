@@ -0,0 +1,18 @@
+ALTER TABLE study_session_answers ADD COLUMN user_id int;
+ALTER TABLE study_session_answers ADD COLUMN collection_id int;
+
+UPDATE study_session_answers a SET user_id = s.user_id, collection_id = s.collection_id
+FROM study_sessions s WHERE s.id = a.session_id;
+
+ALTER TABLE study_session_answers ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE study_session_answers ALTER COLUMN collection_id SET NOT NULL;
+ALTER TABLE study_session_answers ALTER COLUMN session_id DROP NOT NULL;
+
+ALTER TABLE study_session_answers ADD CONSTRAINT fk_user
+    FOREIGN KEY(user_id)
+        REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE study_session_answers ADD CONSTRAINT fk_collection
+    FOREIGN KEY(collection_id)
+        REFERENCES collections(id) ON DELETE CASCADE;
+
+CREATE INDEX study_session_answers_user_idx ON study_session_answers(user_id, answered_at);
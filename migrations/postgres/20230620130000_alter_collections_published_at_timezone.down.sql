@@ -0,0 +1 @@
+ALTER TABLE collections ALTER COLUMN published_at TYPE timestamp USING published_at AT TIME ZONE 'UTC';
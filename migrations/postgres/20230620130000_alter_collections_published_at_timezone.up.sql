@@ -0,0 +1,2 @@
+-- published_at was written in UTC
+ALTER TABLE collections ALTER COLUMN published_at TYPE TIMESTAMP WITH TIME ZONE USING published_at AT TIME ZONE 'UTC';
@@ -0,0 +1,4 @@
+-- history of moved words can reference removed collection, existing rows are not checked
+ALTER TABLE word_history ADD CONSTRAINT fk_collection
+    FOREIGN KEY(collection_id)
+        REFERENCES collections(id) ON DELETE CASCADE NOT VALID;
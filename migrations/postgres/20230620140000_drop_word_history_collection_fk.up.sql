@@ -0,0 +1,2 @@
+-- history is removed by word ids with words, words moved to another collection keep it
+ALTER TABLE word_history DROP CONSTRAINT IF EXISTS fk_collection;
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// AccountArchiveVersion is increased when archive format changes,
+// archives of newer versions are not imported
+const AccountArchiveVersion = 1
+
+// AccountArchive keeps all user data to move it to another account.
+// Collections keep original ids, words are inside of their collections.
+type AccountArchive struct {
+	Version     int           `json:"version"`
+	ExportedAt  time.Time     `json:"exportedAt"`
+	User        ArchiveUser   `json:"user"`
+	Settings    *UserSettings `json:"settings"`
+	Collections []Collection  `json:"collections"`
+}
+
+type ArchiveUser struct {
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// AccountArchiveImport is the result of archive import, CollectionIds maps archive ids to created ones
+type AccountArchiveImport struct {
+	Collections   uint64            `json:"collections"`
+	Words         uint64            `json:"words"`
+	CollectionIds map[uint64]uint64 `json:"collectionIds"`
+}
@@ -10,4 +10,51 @@ type Collection struct {
 	Words     []Word    `json:"words"`
 	LangFrom  string    `json:"langFrom"`
 	LangTo    string    `json:"langTo"`
+
+	Description string     `json:"description"`
+	IsPublic    bool       `json:"isPublic"`
+	PublishedAt *time.Time `json:"publishedAt"`
+
+	Color     string `json:"color"`
+	Icon      string `json:"icon"`
+	Archived  bool   `json:"archived"`
+	SortOrder int64  `json:"sortOrder"`
+
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+
+	SchedulerSettings SchedulerSettings `json:"schedulerSettings"`
+}
+
+const (
+	SchedulerTypeSM2     = "sm2"
+	SchedulerTypeLeitner = "leitner"
+)
+
+// SchedulerSettings selects review algorithm of the collection,
+// LeitnerIntervals are days between reviews for every box
+type SchedulerSettings struct {
+	Type             string   `json:"type"`
+	LeitnerIntervals []uint64 `json:"leitnerIntervals"`
+}
+
+const (
+	CatalogSortByPublishedAt = "publishedAt"
+	CatalogSortByName        = "name"
+	CatalogSortByWords       = "words"
+)
+
+// CatalogFilter filters public collections, empty fields are not applied
+type CatalogFilter struct {
+	LangFrom string
+	LangTo   string
+	Name     string
+	MinWords uint64
+	SortBy   string
+}
+
+// CatalogCollection is a public collection with info about its words and owner
+type CatalogCollection struct {
+	Collection
+	WordsCount uint64 `json:"wordsCount"`
+	OwnerName  string `json:"ownerName"`
 }
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+const (
+	DeletionStatusPending   = "pending"
+	DeletionStatusCompleted = "completed"
+	DeletionStatusFailed    = "failed"
+)
+
+// CollectionDeletion tracks removal of collection data from elasticsearch
+// after the collection row is deleted, so failed cleanup can be retried
+type CollectionDeletion struct {
+	Id              uint64     `json:"id"`
+	CollectionId    uint64     `json:"collectionId"`
+	CollectionName  string     `json:"collectionName"`
+	OwnerId         uint64     `json:"ownerId"`
+	Status          string     `json:"status"`
+	Attempts        uint64     `json:"attempts"`
+	Error           string     `json:"error"`
+	MembersRemoved  uint64     `json:"membersRemoved"`
+	SessionsRemoved uint64     `json:"sessionsRemoved"`
+	WordsRemoved    uint64     `json:"wordsRemoved"`
+	AliasRemoved    bool       `json:"aliasRemoved"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	FinishedAt      *time.Time `json:"finishedAt"`
+}
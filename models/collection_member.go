@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+type CollectionRole string
+
+const (
+	CollectionRoleOwner  CollectionRole = "owner"
+	CollectionRoleEditor CollectionRole = "editor"
+	CollectionRoleViewer CollectionRole = "viewer"
+)
+
+// IsValidMemberRole checks role which can be given to invited user
+func (r CollectionRole) IsValidMemberRole() bool {
+	return r == CollectionRoleEditor || r == CollectionRoleViewer
+}
+
+// CanEdit tells whether role allows to change collection words
+func (r CollectionRole) CanEdit() bool {
+	return r == CollectionRoleOwner || r == CollectionRoleEditor
+}
+
+const (
+	MemberStatusInvited  = "invited"
+	MemberStatusAccepted = "accepted"
+)
+
+type CollectionMember struct {
+	Id           uint64         `json:"id"`
+	CollectionId uint64         `json:"collectionId"`
+	UserId       uint64         `json:"userId"`
+	Role         CollectionRole `json:"role"`
+	Status       string         `json:"status"`
+	InvitedBy    uint64         `json:"invitedBy"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	AcceptedAt   *time.Time     `json:"acceptedAt"`
+
+	User       *User       `json:"user,omitempty"`
+	Collection *Collection `json:"collection,omitempty"`
+}
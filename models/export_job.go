@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ExportFormatPdf  = "pdf"
+	ExportFormatCsv  = "csv"
+	ExportFormatAnki = "anki"
+	ExportFormatJson = "json"
+
+	ExportStatusPending    = "pending"
+	ExportStatusProcessing = "processing"
+	ExportStatusCompleted  = "completed"
+	ExportStatusFailed     = "failed"
+)
+
+func IsValidExportFormat(format string) bool {
+	switch format {
+	case ExportFormatPdf, ExportFormatCsv, ExportFormatAnki, ExportFormatJson:
+		return true
+	}
+
+	return false
+}
+
+// ExportJob is a file of the collection generated in background,
+// Options keep format specific settings, for example pdf layout.
+// File is stored by FileKey in object storage till ExpiresAt.
+type ExportJob struct {
+	Id           uint64          `json:"id"`
+	CollectionId uint64          `json:"collectionId"`
+	OwnerId      uint64          `json:"ownerId"`
+	UserId       uint64          `json:"userId"`
+	Format       string          `json:"format"`
+	Options      json.RawMessage `json:"options"`
+	Status       string          `json:"status"`
+	Attempts     uint64          `json:"attempts"`
+	Error        string          `json:"error"`
+	FileKey      string          `json:"-"`
+	FileName     string          `json:"fileName"`
+	FileSize     uint64          `json:"fileSize"`
+	CreatedAt    time.Time       `json:"createdAt"`
+	StartedAt    *time.Time      `json:"startedAt"`
+	FinishedAt   *time.Time      `json:"finishedAt"`
+	ExpiresAt    *time.Time      `json:"expiresAt"`
+	// NextAttemptAt delays retry of failed attempt
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+}
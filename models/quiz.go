@@ -0,0 +1,50 @@
+package models
+
+type QuizMode string
+
+const (
+	QuizModeMultipleChoice QuizMode = "multipleChoice"
+	QuizModeTyping         QuizMode = "typing"
+	QuizModeReverse        QuizMode = "reverse"
+	QuizModeMixed          QuizMode = "mixed"
+)
+
+func (m QuizMode) IsValid() bool {
+	switch m {
+	case QuizModeMultipleChoice, QuizModeTyping, QuizModeReverse, QuizModeMixed:
+		return true
+	}
+
+	return false
+}
+
+type QuizQuestion struct {
+	WordId  string   `json:"wordId"`
+	Mode    QuizMode `json:"mode"`
+	Prompt  string   `json:"prompt"`
+	Options []string `json:"options,omitempty"`
+}
+
+// QuizAnswer is graded by mode of the generated question, Mode sent by client is replaced with it
+type QuizAnswer struct {
+	WordId string   `json:"wordId"`
+	Mode   QuizMode `json:"mode"`
+	Answer string   `json:"answer"`
+}
+
+type QuizQuestionResult struct {
+	WordId        string   `json:"wordId"`
+	Mode          QuizMode `json:"mode"`
+	Prompt        string   `json:"prompt"`
+	Answer        string   `json:"answer"`
+	CorrectAnswer string   `json:"correctAnswer"`
+	Correct       bool     `json:"correct"`
+	Feedback      string   `json:"feedback"`
+}
+
+type QuizResult struct {
+	Score   float64              `json:"score"`
+	Correct uint64               `json:"correct"`
+	Total   uint64               `json:"total"`
+	Results []QuizQuestionResult `json:"results"`
+}
@@ -0,0 +1,24 @@
+package models
+
+type WordsPerCollection struct {
+	CollectionId   uint64 `json:"collectionId"`
+	CollectionName string `json:"collectionName"`
+	Count          uint64 `json:"count"`
+}
+
+type WordsPerPartOfSpeech struct {
+	PartOfSpeech string `json:"partOfSpeech"`
+	Count        uint64 `json:"count"`
+}
+
+type AnswersPerDay struct {
+	Count uint64 `json:"count"`
+	Date  string `json:"date"`
+}
+
+type ActivityPerDay struct {
+	Date          string `json:"date"`
+	WordsAdded    uint64 `json:"wordsAdded"`
+	WordsReviewed uint64 `json:"wordsReviewed"`
+	Total         uint64 `json:"total"`
+}
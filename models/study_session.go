@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+const (
+	StudySessionModeReview = "review"
+)
+
+type StudySession struct {
+	Id             uint64               `json:"id"`
+	UserId         uint64               `json:"userId"`
+	CollectionId   uint64               `json:"collectionId"`
+	Mode           string               `json:"mode"`
+	QuestionsCount uint64               `json:"questionsCount"`
+	CorrectCount   uint64               `json:"correctCount"`
+	StartedAt      time.Time            `json:"startedAt"`
+	FinishedAt     *time.Time           `json:"finishedAt"`
+	Answers        []StudySessionAnswer `json:"answers,omitempty"`
+	// Questions keeps modes of generated quiz, answers are graded by them
+	Questions []StudySessionQuestion `json:"-"`
+}
+
+type StudySessionQuestion struct {
+	WordId string   `json:"wordId"`
+	Mode   QuizMode `json:"mode"`
+}
+
+// StudySessionAnswer is answer of session or review, review made without session has no SessionId
+type StudySessionAnswer struct {
+	Id           uint64    `json:"id"`
+	SessionId    uint64    `json:"sessionId"`
+	UserId       uint64    `json:"userId"`
+	CollectionId uint64    `json:"collectionId"`
+	WordId       string    `json:"wordId"`
+	Word         string    `json:"word"`
+	Correct      bool      `json:"correct"`
+	AnsweredAt   time.Time `json:"answeredAt"`
+}
+
+type MissedWord struct {
+	WordId       string    `json:"wordId"`
+	Word         string    `json:"word"`
+	Mistakes     uint64    `json:"mistakes"`
+	Attempts     uint64    `json:"attempts"`
+	LastMissedAt time.Time `json:"lastMissedAt"`
+}
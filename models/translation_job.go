@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	TranslationStatusPending    = "pending"
+	TranslationStatusProcessing = "processing"
+	TranslationStatusCompleted  = "completed"
+	TranslationStatusFailed     = "failed"
+)
+
+// TranslationJob translates imported Words of the collection in background,
+// words which got translation before the job is run are kept as is
+type TranslationJob struct {
+	Id           uint64     `json:"id"`
+	CollectionId uint64     `json:"collectionId"`
+	OwnerId      uint64     `json:"ownerId"`
+	UserId       uint64     `json:"userId"`
+	Words        []string   `json:"-"`
+	Status       string     `json:"status"`
+	Attempts     uint64     `json:"attempts"`
+	Translated   uint64     `json:"translated"`
+	Error        string     `json:"error"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	StartedAt    *time.Time `json:"startedAt"`
+	FinishedAt   *time.Time `json:"finishedAt"`
+	// NextAttemptAt delays retry of failed attempt
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+}
@@ -14,7 +14,32 @@ type User struct {
 }
 
 type UserSettings struct {
-	Id       uint64 `json:"id"`
-	UserId   uint64 `json:"userId"`
-	Language string `json:"language"`
+	Id        uint64 `json:"id"`
+	UserId    uint64 `json:"userId"`
+	Language  string `json:"language"`
+	Timezone  string `json:"timezone"`
+	DailyGoal uint64 `json:"dailyGoal"`
+}
+
+// Location returns user timezone, UTC is used when timezone is unknown
+func (s *UserSettings) Location() *time.Location {
+	if s == nil || s.Timezone == "" {
+		return time.UTC
+	}
+
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+type Streak struct {
+	Current          uint64 `json:"current"`
+	Longest          uint64 `json:"longest"`
+	DailyGoal        uint64 `json:"dailyGoal"`
+	TodayProgress    uint64 `json:"todayProgress"`
+	GoalReachedToday bool   `json:"goalReachedToday"`
+	Timezone         string `json:"timezone"`
 }
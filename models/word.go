@@ -1,24 +1,173 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
+// Word keeps main Translation and Scentance for old clients,
+// they are always the first items of Translations and Sentences
 type Word struct {
-	Id           string    `json:"id"`
-	CollectionId uint64    `json:"collectionId"`
-	Word         string    `json:"word"`
-	Translation  string    `json:"translation"`
-	PartOfSpeech string    `json:"partOfSpeech"`
-	Scentance    string    `json:"scentance"`
-	CreatedAt    time.Time `json:"createdAt"`
+	Id            string       `json:"id"`
+	CollectionId  uint64       `json:"collectionId"`
+	Word          string       `json:"word"`
+	Translation   string       `json:"translation"`
+	Translations  []string     `json:"translations"`
+	PartOfSpeech  string       `json:"partOfSpeech"`
+	Scentance     string       `json:"scentance"`
+	Sentences     []string     `json:"sentences"`
+	Synonyms      []string     `json:"synonyms"`
+	Antonyms      []string     `json:"antonyms"`
+	Note          string       `json:"note"`
+	Transcription string       `json:"transcription"`
+	Tags          []string     `json:"tags"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	DeletedAt     *time.Time   `json:"deletedAt,omitempty"`
+	Progress      WordProgress `json:"progress"`
+}
+
+// MergeVariants puts main value first to the list of variants without empty
+// values and duplicates, the first variant is main if main value is empty
+func MergeVariants(main string, variants []string) (string, []string) {
+	merged := NonEmpty(append([]string{main}, variants...))
+	if len(merged) == 0 {
+		return "", []string{}
+	}
+
+	return merged[0], merged
+}
+
+// NormalizeTags trims and lowercases tags, so tags which differ only by case are the same tag
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, t := range tags {
+		normalized = append(normalized, strings.ToLower(t))
+	}
+
+	return NonEmpty(normalized)
+}
+
+// NonEmpty trims values and skips empty values and duplicates
+func NonEmpty(values []string) []string {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+
+		result = append(result, v)
+	}
+
+	return result
+}
+
+// WordProgress keeps spaced repetition state of the word.
+// Interval is stored in days, Box is used by Leitner scheduler only.
+type WordProgress struct {
+	EaseFactor     float64      `json:"easeFactor"`
+	Interval       uint64       `json:"interval"`
+	Repetitions    uint64       `json:"repetitions"`
+	Box            uint64       `json:"box"`
+	Mastery        MasteryLevel `json:"mastery"`
+	DueDate        *time.Time   `json:"dueDate"`
+	LastReviewedAt *time.Time   `json:"lastReviewedAt"`
+}
+
+type MasteryLevel string
+
+const (
+	MasteryLevelNew      MasteryLevel = "new"
+	MasteryLevelLearning MasteryLevel = "learning"
+	MasteryLevelKnown    MasteryLevel = "known"
+	MasteryLevelMastered MasteryLevel = "mastered"
+)
+
+func (l MasteryLevel) IsValid() bool {
+	switch l {
+	case MasteryLevelNew, MasteryLevelLearning, MasteryLevelKnown, MasteryLevelMastered:
+		return true
+	}
+
+	return false
+}
+
+type ReviewGrade string
+
+const (
+	ReviewGradeAgain ReviewGrade = "again"
+	ReviewGradeHard  ReviewGrade = "hard"
+	ReviewGradeGood  ReviewGrade = "good"
+	ReviewGradeEasy  ReviewGrade = "easy"
+)
+
+func (g ReviewGrade) IsValid() bool {
+	switch g {
+	case ReviewGradeAgain, ReviewGradeHard, ReviewGradeGood, ReviewGradeEasy:
+		return true
+	}
+
+	return false
 }
 
 type SearchSettings struct {
 	TextForSearch string   `json:"textForSearch"`
 	SearchBy      string   `json:"searchBy"`
 	PartsOfSpeech []string `json:"partsOfSpeech"`
+
+	WordsFilter
+}
+
+const (
+	WordsSortByCreatedAt = "createdAt"
+	WordsSortByMastery   = "mastery"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// WordsFilter narrows and orders words list,
+// word must have all of the Tags to match
+type WordsFilter struct {
+	Mastery   []MasteryLevel `json:"mastery"`
+	Tags      []string       `json:"tags"`
+	SortBy    string         `json:"sortBy"`
+	SortOrder string         `json:"sortOrder"`
+}
+
+type TagCount struct {
+	Name  string `json:"name"`
+	Count uint64 `json:"count"`
 }
 
 type WordsAddedPerTime struct {
 	Count uint64 `json:"count"`
 	Date  string `json:"date"`
 }
+
+// ConflictStrategy decides what to do with the word
+// when target collection already has the same one
+type ConflictStrategy string
+
+const (
+	ConflictStrategySkip      ConflictStrategy = "skip"
+	ConflictStrategyOverwrite ConflictStrategy = "overwrite"
+	ConflictStrategyKeepBoth  ConflictStrategy = "keepBoth"
+)
+
+func (s ConflictStrategy) IsValid() bool {
+	switch s {
+	case ConflictStrategySkip, ConflictStrategyOverwrite, ConflictStrategyKeepBoth:
+		return true
+	}
+
+	return false
+}
+
+type WordsTransferResult struct {
+	Transferred uint64   `json:"transferred"`
+	Overwritten uint64   `json:"overwritten"`
+	Skipped     []string `json:"skipped"`
+}
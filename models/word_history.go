@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+const (
+	WordHistoryActionUpdate = "update"
+	WordHistoryActionRevert = "revert"
+)
+
+// WordHistory is a single change of the word, Snapshot keeps
+// editable fields of the word as they were before the change
+type WordHistory struct {
+	Id           uint64       `json:"id"`
+	WordId       string       `json:"wordId"`
+	CollectionId uint64       `json:"collectionId"`
+	OwnerId      uint64       `json:"ownerId"`
+	ActorId      uint64       `json:"actorId"`
+	Action       string       `json:"action"`
+	RevertedFrom *uint64      `json:"revertedFrom,omitempty"`
+	Changes      []WordChange `json:"changes"`
+	Snapshot     WordSnapshot `json:"snapshot"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	Actor        *User        `json:"actor,omitempty"`
+}
+
+type WordChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// WordSnapshot is the editable part of the word
+type WordSnapshot struct {
+	Word          string   `json:"word"`
+	Translations  []string `json:"translations"`
+	PartOfSpeech  string   `json:"partOfSpeech"`
+	Sentences     []string `json:"sentences"`
+	Synonyms      []string `json:"synonyms"`
+	Antonyms      []string `json:"antonyms"`
+	Note          string   `json:"note"`
+	Transcription string   `json:"transcription"`
+	Tags          []string `json:"tags"`
+}
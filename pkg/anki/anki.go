@@ -0,0 +1,424 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// fieldSeparator divides fields of the note
+	fieldSeparator = "\x1f"
+
+	collectionFile       = "collection.anki2"
+	collectionFileLegacy = "collection.anki21"
+	collectionFileZstd   = "collection.anki21b"
+	mediaFile            = "media"
+
+	// maxCollectionSize limits unpacked collection database, 100mb
+	maxCollectionSize = 100 << 20
+)
+
+var (
+	ErrCollectionNotFound = errors.New("package has no anki collection")
+	ErrCompressedPackage  = errors.New("package is compressed, export it with support of older anki versions")
+
+	tagsRegexp = regexp.MustCompile(`<[^>]*>`)
+	brRegexp   = regexp.MustCompile(`(?i)<br\s*/?>|<div>`)
+)
+
+// Card is basic note type with front and back fields
+type Card struct {
+	Id    string
+	Front string
+	Back  string
+	Tags  []string
+}
+
+// Export creates .apkg package with one deck of basic front/back notes
+func Export(deckName string, cards []Card) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "anki-export")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	dbPath := filepath.Join(dir, collectionFile)
+	err = writeCollection(dbPath, deckName, cards)
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := os.ReadFile(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{name: collectionFile, data: collection},
+		{name: mediaFile, data: []byte("{}")},
+	}
+	for _, f := range files {
+		w, err := zipWriter.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = w.Write(f.data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = zipWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Import reads notes of .apkg package, the first field is used as front
+// and the second as back, html is removed from the fields
+func Import(data []byte) ([]Card, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	// new anki versions keep actual collection in anki21 and stub in anki2
+	var collection *zip.File
+	compressed := false
+	for _, f := range zipReader.File {
+		if f.Name == collectionFileLegacy || (f.Name == collectionFile && collection == nil) {
+			collection = f
+		}
+		if f.Name == collectionFileZstd {
+			compressed = true
+		}
+	}
+
+	if compressed && (collection == nil || collection.Name != collectionFileLegacy) {
+		return nil, ErrCompressedPackage
+	}
+
+	if collection == nil {
+		return nil, ErrCollectionNotFound
+	}
+
+	dir, err := os.MkdirTemp("", "anki-import")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	dbPath := filepath.Join(dir, collectionFile)
+	err = unzipFile(collection, dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return readCards(dbPath)
+}
+
+func unzipFile(f *zip.File, path string) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	n, err := io.Copy(out, io.LimitReader(r, maxCollectionSize+1))
+	if err != nil {
+		return err
+	}
+
+	if n > maxCollectionSize {
+		return errors.New("anki collection is too large")
+	}
+
+	return nil
+}
+
+func readCards(dbPath string) ([]Card, error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", dbPath))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT guid, flds, tags FROM notes ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("anki collection is not valid: %w", err)
+	}
+	defer rows.Close()
+
+	cards := []Card{}
+	for rows.Next() {
+		var guid, fields, tags string
+		err := rows.Scan(&guid, &fields, &tags)
+		if err != nil {
+			return nil, err
+		}
+
+		values := strings.Split(fields, fieldSeparator)
+		card := Card{
+			Id:    guid,
+			Front: stripHtml(values[0]),
+			Tags:  strings.Fields(tags),
+		}
+		if len(values) > 1 {
+			card.Back = stripHtml(values[1])
+		}
+
+		cards = append(cards, card)
+	}
+
+	return cards, rows.Err()
+}
+
+func stripHtml(value string) string {
+	value = brRegexp.ReplaceAllString(value, "\n")
+	value = tagsRegexp.ReplaceAllString(value, "")
+	value = html.UnescapeString(value)
+	value = strings.ReplaceAll(value, "\u00a0", " ")
+
+	return strings.TrimSpace(value)
+}
+
+func toHtml(value string) string {
+	return strings.ReplaceAll(html.EscapeString(value), "\n", "<br>")
+}
+
+const schema = `
+CREATE TABLE col (
+    id integer primary key, crt integer not null, mod integer not null, scm integer not null,
+    ver integer not null, dty integer not null, usn integer not null, ls integer not null,
+    conf text not null, models text not null, decks text not null, dconf text not null, tags text not null
+);
+CREATE TABLE notes (
+    id integer primary key, guid text not null, mid integer not null, mod integer not null,
+    usn integer not null, tags text not null, flds text not null, sfld integer not null,
+    csum integer not null, flags integer not null, data text not null
+);
+CREATE TABLE cards (
+    id integer primary key, nid integer not null, did integer not null, ord integer not null,
+    mod integer not null, usn integer not null, type integer not null, queue integer not null,
+    due integer not null, ivl integer not null, factor integer not null, reps integer not null,
+    lapses integer not null, left integer not null, odue integer not null, odid integer not null,
+    flags integer not null, data text not null
+);
+CREATE TABLE revlog (
+    id integer primary key, cid integer not null, usn integer not null, ivl integer not null,
+    lastIvl integer not null, factor integer not null, time integer not null, type integer not null
+);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn on notes (usn);
+CREATE INDEX ix_cards_usn on cards (usn);
+CREATE INDEX ix_revlog_usn on revlog (usn);
+CREATE INDEX ix_cards_nid on cards (nid);
+CREATE INDEX ix_cards_sched on cards (did, queue, due);
+CREATE INDEX ix_revlog_cid on revlog (cid);
+CREATE INDEX ix_notes_csum on notes (csum);
+`
+
+func writeCollection(dbPath, deckName string, cards []Card) error {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(schema)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	modelId := now.UnixMilli()
+	deckId := modelId + 1
+
+	models, decks, dconf, conf, err := collectionConfig(deckName, modelId, deckId, now, len(cards))
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')",
+		now.Unix(), now.UnixMilli(), now.UnixMilli(), conf, models, decks, dconf)
+	if err != nil {
+		return err
+	}
+
+	noteStmt, err := tx.Prepare("INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')")
+	if err != nil {
+		return err
+	}
+	defer noteStmt.Close()
+
+	cardStmt, err := tx.Prepare("INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')")
+	if err != nil {
+		return err
+	}
+	defer cardStmt.Close()
+
+	for i, c := range cards {
+		// ids are timestamps in milliseconds in anki
+		id := modelId + 2 + int64(i)
+		front := toHtml(c.Front)
+
+		tags := ""
+		if len(c.Tags) > 0 {
+			normalized := []string{}
+			for _, t := range c.Tags {
+				normalized = append(normalized, strings.Join(strings.Fields(t), "_"))
+			}
+			tags = " " + strings.Join(normalized, " ") + " "
+		}
+
+		_, err = noteStmt.Exec(id, guid(c.Id, i), modelId, now.Unix(), tags,
+			front+fieldSeparator+toHtml(c.Back), front, checksum(c.Front))
+		if err != nil {
+			return err
+		}
+
+		_, err = cardStmt.Exec(id, id, deckId, now.Unix(), i+1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// guid keeps the same note for the same word, so repeated import updates notes in anki
+func guid(id string, index int) string {
+	if id == "" {
+		id = fmt.Sprintf("%d-%d", time.Now().UnixNano(), index)
+	}
+
+	hash := sha1.Sum([]byte(id))
+	return base64.RawStdEncoding.EncodeToString(hash[:8])
+}
+
+// checksum is used by anki to find duplicates, it is the first 8 digits of sha1 of the first field
+func checksum(value string) int64 {
+	hash := sha1.Sum([]byte(value))
+	return int64(binary.BigEndian.Uint32(hash[:4]))
+}
+
+func collectionConfig(deckName string, modelId, deckId int64, now time.Time, cardsCount int) (string, string, string, string, error) {
+	field := func(name string, ord int) map[string]interface{} {
+		return map[string]interface{}{
+			"name": name, "ord": ord, "sticky": false, "rtl": false,
+			"font": "Arial", "size": 20, "media": []string{},
+		}
+	}
+
+	models := map[string]interface{}{
+		fmt.Sprint(modelId): map[string]interface{}{
+			"id":    modelId,
+			"name":  "Basic (vocabulary)",
+			"type":  0,
+			"mod":   now.Unix(),
+			"usn":   -1,
+			"sortf": 0,
+			"did":   deckId,
+			"tmpls": []map[string]interface{}{{
+				"name":  "Card 1",
+				"ord":   0,
+				"qfmt":  "{{Front}}",
+				"afmt":  "{{FrontSide}}\n\n<hr id=answer>\n\n{{Back}}",
+				"did":   nil,
+				"bqfmt": "",
+				"bafmt": "",
+			}},
+			"flds":      []map[string]interface{}{field("Front", 0), field("Back", 1)},
+			"css":       ".card {\n font-family: arial;\n font-size: 20px;\n text-align: center;\n color: black;\n background-color: white;\n}\n",
+			"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+			"latexPost": "\\end{document}",
+			"tags":      []string{},
+			"vers":      []string{},
+			"req":       []interface{}{[]interface{}{0, "all", []int{0}}},
+		},
+	}
+
+	deck := func(id int64, name string) map[string]interface{} {
+		return map[string]interface{}{
+			"id": id, "name": name, "mod": now.Unix(), "usn": -1, "desc": "",
+			"lrnToday": []int{0, 0}, "revToday": []int{0, 0}, "newToday": []int{0, 0}, "timeToday": []int{0, 0},
+			"collapsed": false, "dyn": 0, "conf": 1, "extendNew": 10, "extendRev": 50,
+		}
+	}
+
+	decks := map[string]interface{}{
+		"1":                deck(1, "Default"),
+		fmt.Sprint(deckId): deck(deckId, deckName),
+	}
+
+	dconf := map[string]interface{}{
+		"1": map[string]interface{}{
+			"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true,
+			"timer": 0, "replayq": true, "dyn": false,
+			"new": map[string]interface{}{
+				"delays": []int{1, 10}, "ints": []int{1, 4, 7}, "initialFactor": 2500,
+				"order": 1, "perDay": 20, "bury": true,
+			},
+			"rev": map[string]interface{}{
+				"perDay": 200, "ease4": 1.3, "ivlFct": 1, "maxIvl": 36500, "bury": true, "hardFactor": 1.2,
+			},
+			"lapse": map[string]interface{}{
+				"delays": []int{10}, "mult": 0, "minInt": 1, "leechFails": 8, "leechAction": 0,
+			},
+		},
+	}
+
+	conf := map[string]interface{}{
+		"nextPos": cardsCount + 1, "estTimes": true, "activeDecks": []int64{deckId}, "sortType": "noteFld",
+		"timeLim": 0, "sortBackwards": false, "addToCur": true, "curDeck": deckId, "newBury": true,
+		"newSpread": 0, "dueCounts": true, "curModel": fmt.Sprint(modelId), "collapseTime": 1200,
+	}
+
+	result := []string{}
+	for _, v := range []interface{}{models, decks, dconf, conf} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", "", "", "", err
+		}
+		result = append(result, string(data))
+	}
+
+	return result[0], result[1], result[2], result[3], nil
+}
@@ -0,0 +1,229 @@
+package extractor
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	FormatText = "text"
+	FormatSRT  = "srt"
+	FormatVTT  = "vtt"
+
+	// minWordLength skips single letters and initials
+	minWordLength = 2
+
+	// maxSentences limits context sentences of one candidate
+	maxSentences = 3
+
+	// maxSentenceLength in runes, longer sentences are cut around the word
+	maxSentenceLength = 300
+)
+
+var (
+	subtitleTagsRegexp   = regexp.MustCompile(`<[^>]*>|\{[^}]*\}`)
+	sentenceEndRegexp    = regexp.MustCompile(`([.!?…]+["'»”)]*)\s+`)
+	paragraphBreakRegexp = regexp.MustCompile(`\n\s*\n`)
+)
+
+// Candidate is a word of the text with its count and sentences where it is used
+type Candidate struct {
+	Word      string   `json:"word"`
+	Count     int      `json:"count"`
+	Sentences []string `json:"sentences"`
+}
+
+// FormatByFileName returns format by file extension, text is used for unknown extensions
+func FormatByFileName(name string) string {
+	switch strings.ToLower(strings.TrimPrefix(filepath.Ext(name), ".")) {
+	case FormatSRT:
+		return FormatSRT
+	case FormatVTT:
+		return FormatVTT
+	}
+
+	return FormatText
+}
+
+// PlainText returns text of subtitles without numbers, timings and tags, text is returned as is
+func PlainText(content string, format string) (string, error) {
+	content = strings.TrimPrefix(content, "\ufeff")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	switch format {
+	case FormatText, "":
+		return content, nil
+	case FormatSRT, FormatVTT:
+		return subtitlesText(content), nil
+	}
+
+	return "", fmt.Errorf("format must be one of %s, %s, %s", FormatText, FormatSRT, FormatVTT)
+}
+
+// subtitlesText keeps text of cues, srt and vtt cues both have timing line with "-->"
+// followed by text lines, vtt header, notes and styles have no timing and are skipped
+func subtitlesText(content string) string {
+	cues := []string{}
+	for _, block := range paragraphBreakRegexp.Split(content, -1) {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+
+		timing := -1
+		for i, line := range lines {
+			if strings.Contains(line, "-->") {
+				timing = i
+				break
+			}
+		}
+
+		if timing == -1 || strings.HasPrefix(lines[0], "NOTE") {
+			continue
+		}
+
+		for _, line := range lines[timing+1:] {
+			line = strings.TrimSpace(subtitleTagsRegexp.ReplaceAllString(line, ""))
+			// dialogue lines start with dash
+			line = strings.TrimSpace(strings.TrimPrefix(line, "-"))
+			if line != "" {
+				cues = append(cues, line)
+			}
+		}
+	}
+
+	// sentences often continue in the next cue
+	return strings.Join(cues, " ")
+}
+
+// Sentences splits text by sentence punctuation and paragraphs
+func Sentences(text string) []string {
+	sentences := []string{}
+	for _, paragraph := range paragraphBreakRegexp.Split(text, -1) {
+		paragraph = strings.Join(strings.Fields(paragraph), " ")
+		paragraph = sentenceEndRegexp.ReplaceAllString(paragraph, "$1\n")
+
+		for _, s := range strings.Split(paragraph, "\n") {
+			if s = strings.TrimSpace(s); s != "" {
+				sentences = append(sentences, s)
+			}
+		}
+	}
+
+	return sentences
+}
+
+// Tokenize returns lowercase words of the sentence, apostrophes and hyphens
+// inside of words are kept, possessive 's is removed, tokens with digits are skipped
+func Tokenize(sentence string) []string {
+	words := []string{}
+	token := []rune{}
+	hasDigit := false
+
+	flush := func() {
+		word := strings.TrimSuffix(strings.ToLower(strings.Trim(string(token), "'-")), "'s")
+		if !hasDigit && len([]rune(word)) >= minWordLength {
+			words = append(words, word)
+		}
+		token = token[:0]
+		hasDigit = false
+	}
+
+	for _, r := range sentence {
+		switch {
+		case r == '’' || r == 'ʼ':
+			token = append(token, '\'')
+		case unicode.IsLetter(r) || unicode.IsMark(r) || r == '\'' || r == '-':
+			token = append(token, r)
+		case unicode.IsDigit(r):
+			token = append(token, r)
+			hasDigit = true
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return words
+}
+
+// Extract returns words of the text ranked by frequency, words with the same count
+// keep order of the first use. Words from stopWords are skipped.
+func Extract(text string, stopWords map[string]bool) []Candidate {
+	candidates := []*Candidate{}
+	index := map[string]*Candidate{}
+
+	for _, sentence := range Sentences(text) {
+		used := map[string]bool{}
+		for _, word := range Tokenize(sentence) {
+			if stopWords[word] {
+				continue
+			}
+
+			candidate, ok := index[word]
+			if !ok {
+				candidate = &Candidate{Word: word, Sentences: []string{}}
+				index[word] = candidate
+				candidates = append(candidates, candidate)
+			}
+
+			candidate.Count++
+			if !used[word] && len(candidate.Sentences) < maxSentences {
+				candidate.Sentences = append(candidate.Sentences, cutSentence(sentence, word))
+			}
+			used[word] = true
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Count > candidates[j].Count
+	})
+
+	result := make([]Candidate, 0, len(candidates))
+	for _, c := range candidates {
+		result = append(result, *c)
+	}
+
+	return result
+}
+
+// cutSentence keeps part of long sentence around the first use of the word
+func cutSentence(sentence, word string) string {
+	runes := []rune(sentence)
+	if len(runes) <= maxSentenceLength {
+		return sentence
+	}
+
+	// lower case has the same runes count, so position is the same in the sentence
+	lower := strings.ToLower(sentence)
+	position := 0
+	if i := strings.Index(lower, word); i > 0 {
+		position = len([]rune(lower[:i]))
+	}
+
+	from := position - maxSentenceLength/2
+	if from < 0 {
+		from = 0
+	}
+
+	to := from + maxSentenceLength
+	if to > len(runes) {
+		to = len(runes)
+		from = to - maxSentenceLength
+	}
+
+	// cut words are dropped
+	cut := string(runes[from:to])
+	if from > 0 {
+		cut = "…" + strings.TrimSpace(cut[strings.Index(cut, " ")+1:])
+	}
+	if to < len(runes) {
+		if i := strings.LastIndex(cut, " "); i > 0 {
+			cut = cut[:i]
+		}
+		cut = strings.TrimSpace(cut) + "…"
+	}
+
+	return cut
+}
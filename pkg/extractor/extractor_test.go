@@ -0,0 +1,210 @@
+package extractor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		sentence string
+		want     []string
+	}{
+		{
+			name:     "lower case without punctuation",
+			sentence: "The Dog, barks!",
+			want:     []string{"the", "dog", "barks"},
+		},
+		{
+			name:     "inner apostrophes and hyphens are kept",
+			sentence: "It's five o’clock, a well-known -fact-",
+			want:     []string{"it", "five", "o'clock", "well-known", "fact"},
+		},
+		{
+			name:     "possessive is removed",
+			sentence: "The dog's bowl and the cats' toys",
+			want:     []string{"the", "dog", "bowl", "and", "the", "cats", "toys"},
+		},
+		{
+			name:     "digits and single letters are skipped",
+			sentence: "I saw 3 mp3 files in room B",
+			want:     []string{"saw", "files", "in", "room"},
+		},
+		{
+			name:     "not latin letters",
+			sentence: "Привіт, світе — café",
+			want:     []string{"привіт", "світе", "café"},
+		},
+		{
+			name:     "empty",
+			sentence: " ... ",
+			want:     []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.sentence); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		stopWords map[string]bool
+		want      []Candidate
+	}{
+		{
+			name:      "ranked by count with first use order",
+			text:      "The cat sleeps. The dog barks at the cat.",
+			stopWords: map[string]bool{"the": true, "at": true},
+			want: []Candidate{
+				{Word: "cat", Count: 2, Sentences: []string{"The cat sleeps.", "The dog barks at the cat."}},
+				{Word: "sleeps", Count: 1, Sentences: []string{"The cat sleeps."}},
+				{Word: "dog", Count: 1, Sentences: []string{"The dog barks at the cat."}},
+				{Word: "barks", Count: 1, Sentences: []string{"The dog barks at the cat."}},
+			},
+		},
+		{
+			name: "sentence is added once and limited",
+			text: "Run, run! Run away.\n\nRun home. Run fast",
+			want: []Candidate{
+				{Word: "run", Count: 5, Sentences: []string{"Run, run!", "Run away.", "Run home."}},
+				{Word: "away", Count: 1, Sentences: []string{"Run away."}},
+				{Word: "home", Count: 1, Sentences: []string{"Run home."}},
+				{Word: "fast", Count: 1, Sentences: []string{"Run fast"}},
+			},
+		},
+		{
+			name: "empty text",
+			text: "  ",
+			want: []Candidate{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Extract(tt.text, tt.stopWords); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCutSentence(t *testing.T) {
+	filler := strings.Repeat("filler ", 100)
+
+	tests := []struct {
+		name     string
+		sentence string
+		word     string
+		prefix   bool
+		suffix   bool
+	}{
+		{
+			name:     "short sentence is not cut",
+			sentence: "The target is here.",
+			word:     "target",
+		},
+		{
+			name:     "word at the start",
+			sentence: "Target " + filler + filler,
+			word:     "target",
+			suffix:   true,
+		},
+		{
+			name:     "word in the middle",
+			sentence: filler + "target " + filler,
+			word:     "target",
+			prefix:   true,
+			suffix:   true,
+		},
+		{
+			name:     "word at the end",
+			sentence: filler + filler + "target",
+			word:     "target",
+			prefix:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cutSentence(tt.sentence, tt.word)
+
+			if !tt.prefix && !tt.suffix && got != tt.sentence {
+				t.Errorf("expected sentence as is, got %q", got)
+			}
+			if len([]rune(got)) > maxSentenceLength+2 {
+				t.Errorf("expected at most %d runes, got %d", maxSentenceLength+2, len([]rune(got)))
+			}
+			if !strings.Contains(strings.ToLower(got), tt.word) {
+				t.Errorf("expected %q to contain %q", got, tt.word)
+			}
+			if strings.HasPrefix(got, "…") != tt.prefix {
+				t.Errorf("expected prefix %t, got %q", tt.prefix, got)
+			}
+			if strings.HasSuffix(got, "…") != tt.suffix {
+				t.Errorf("expected suffix %t, got %q", tt.suffix, got)
+			}
+			if !tt.prefix && !tt.suffix {
+				return
+			}
+			// cut words are dropped, so only whole fillers are left
+			for _, word := range strings.Fields(strings.Trim(got, "…")) {
+				if word != "filler" && strings.ToLower(word) != tt.word {
+					t.Errorf("expected whole words, got %q in %q", word, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		format  string
+		want    string
+	}{
+		{
+			name:    "text is kept",
+			content: "\ufeffHello.\r\nWorld.",
+			format:  FormatText,
+			want:    "Hello.\nWorld.",
+		},
+		{
+			name:    "srt",
+			content: "1\r\n00:00:01,000 --> 00:00:02,000\r\n<i>Hello</i>\r\n- there.\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\n{\\an8}Bye.\r\n",
+			format:  FormatSRT,
+			want:    "Hello there. Bye.",
+		},
+		{
+			name:    "vtt",
+			content: "WEBVTT\n\nNOTE comment --> here\n\n00:01.000 --> 00:02.000\nHello.\n",
+			format:  FormatVTT,
+			want:    "Hello.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PlainText(tt.content, tt.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := PlainText("text", "docx"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
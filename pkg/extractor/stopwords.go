@@ -0,0 +1,30 @@
+package extractor
+
+import "strings"
+
+// stopWords are the most frequent function words, they are known by any learner
+// and only push useful words down the list
+var stopWords = map[string]string{
+	"en": `a about above after again against all am an and any are as at be because been before being
+		below between both but by can could did do does doing don't down during each few for from further
+		had has have having he her here hers herself him himself his how i i'd i'll i'm i've if in into is
+		isn't it it's its itself just let let's me more most my myself no nor not now of off on once only or
+		other our ours ourselves out over own same she should so some such than that that's the their
+		theirs them themselves then there there's these they they're this those through to too under until
+		up very was wasn't we we're were what when where which while who whom why will with won't would
+		you you're you've your yours yourself yourselves oh okay ok yeah yes hey uh um`,
+	"ua": `а але б би був була були було бути в вам вас весь во вона вони воно все всі ви від він да для до
+		є же з за зі і із й к коли куди ми мене мені мій на над нам нас не нею ні ну о об однак окрім от по
+		під при про та так також там те теж ти то тобі тому тут у уже хто це цей ці через чи що щоб як який
+		якщо я так ага ой`,
+}
+
+// StopWords returns stop words of the language, unknown language has no stop words
+func StopWords(lang string) map[string]bool {
+	words := map[string]bool{}
+	for _, w := range strings.Fields(stopWords[lang]) {
+		words[w] = true
+	}
+
+	return words
+}
@@ -0,0 +1,224 @@
+package kindle
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// categoryMastered is set by kindle for words marked as mastered in vocabulary builder
+	categoryMastered = 100
+
+	sqliteHeader = "SQLite format 3\x00"
+)
+
+var (
+	ErrNotVocabulary = errors.New("file is not kindle vocabulary database")
+)
+
+// Book is a book where words were looked up
+type Book struct {
+	Id      string `json:"id"`
+	Title   string `json:"title"`
+	Authors string `json:"authors"`
+	Lang    string `json:"lang"`
+	Words   int    `json:"words"`
+}
+
+// Usage is the sentence where the word was looked up
+type Usage struct {
+	BookId     string    `json:"bookId"`
+	Text       string    `json:"text"`
+	LookedUpAt time.Time `json:"lookedUpAt"`
+}
+
+// Word is looked up word, Stem is its dictionary form
+type Word struct {
+	Id       string  `json:"id"`
+	Word     string  `json:"word"`
+	Stem     string  `json:"stem"`
+	Lang     string  `json:"lang"`
+	Mastered bool    `json:"mastered"`
+	Usages   []Usage `json:"usages"`
+}
+
+// Vocabulary is content of vocab.db of kindle vocabulary builder
+type Vocabulary struct {
+	Books []Book `json:"books"`
+	Words []Word `json:"words"`
+}
+
+// Read returns books and words of vocab.db, words are ordered by the last lookup
+func Read(data []byte) (*Vocabulary, error) {
+	if !bytes.HasPrefix(data, []byte(sqliteHeader)) {
+		return nil, ErrNotVocabulary
+	}
+
+	dir, err := os.MkdirTemp("", "kindle-import")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	dbPath := filepath.Join(dir, "vocab.db")
+	err = os.WriteFile(dbPath, data, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", dbPath))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	books, err := readBooks(db)
+	if err != nil {
+		return nil, err
+	}
+
+	words, err := readWords(db)
+	if err != nil {
+		return nil, err
+	}
+
+	lookedUp, err := readUsages(db, words, books)
+	if err != nil {
+		return nil, err
+	}
+
+	vocabulary := &Vocabulary{
+		Books: []Book{},
+		Words: []Word{},
+	}
+
+	for _, b := range books {
+		if b.Words > 0 {
+			vocabulary.Books = append(vocabulary.Books, *b)
+		}
+	}
+
+	sort.Slice(vocabulary.Books, func(i, j int) bool {
+		return strings.ToLower(vocabulary.Books[i].Title) < strings.ToLower(vocabulary.Books[j].Title)
+	})
+
+	for _, w := range lookedUp {
+		vocabulary.Words = append(vocabulary.Words, *w)
+	}
+
+	return vocabulary, nil
+}
+
+func readBooks(db *sql.DB) (map[string]*Book, error) {
+	rows, err := db.Query("SELECT id, title, authors, lang FROM BOOK_INFO")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotVocabulary, err.Error())
+	}
+	defer rows.Close()
+
+	books := map[string]*Book{}
+	for rows.Next() {
+		var id string
+		var title, authors, lang sql.NullString
+		err := rows.Scan(&id, &title, &authors, &lang)
+		if err != nil {
+			return nil, err
+		}
+
+		books[id] = &Book{
+			Id:      id,
+			Title:   strings.TrimSpace(title.String),
+			Authors: strings.TrimSpace(authors.String),
+			Lang:    lang.String,
+		}
+	}
+
+	return books, rows.Err()
+}
+
+func readWords(db *sql.DB) (map[string]*Word, error) {
+	rows, err := db.Query("SELECT id, word, stem, lang, category FROM WORDS")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotVocabulary, err.Error())
+	}
+	defer rows.Close()
+
+	words := map[string]*Word{}
+	for rows.Next() {
+		var id string
+		var word, stem, lang sql.NullString
+		var category sql.NullInt64
+		err := rows.Scan(&id, &word, &stem, &lang, &category)
+		if err != nil {
+			return nil, err
+		}
+
+		words[id] = &Word{
+			Id:       id,
+			Word:     strings.TrimSpace(word.String),
+			Stem:     strings.TrimSpace(stem.String),
+			Lang:     lang.String,
+			Mastered: category.Int64 == categoryMastered,
+			Usages:   []Usage{},
+		}
+	}
+
+	return words, rows.Err()
+}
+
+// readUsages adds lookups to words and counts words of books,
+// returns looked up words ordered by the latest lookup
+func readUsages(db *sql.DB, words map[string]*Word, books map[string]*Book) ([]*Word, error) {
+	rows, err := db.Query("SELECT word_key, book_key, usage, timestamp FROM LOOKUPS ORDER BY timestamp DESC")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotVocabulary, err.Error())
+	}
+	defer rows.Close()
+
+	order := []*Word{}
+	bookWords := map[string]map[string]bool{}
+	for rows.Next() {
+		var wordKey, bookKey, usage sql.NullString
+		var timestamp sql.NullInt64
+		err := rows.Scan(&wordKey, &bookKey, &usage, &timestamp)
+		if err != nil {
+			return nil, err
+		}
+
+		word, ok := words[wordKey.String]
+		if !ok {
+			continue
+		}
+
+		if len(word.Usages) == 0 {
+			order = append(order, word)
+		}
+
+		word.Usages = append(word.Usages, Usage{
+			BookId:     bookKey.String,
+			Text:       strings.TrimSpace(usage.String),
+			LookedUpAt: time.UnixMilli(timestamp.Int64),
+		})
+
+		if book, ok := books[bookKey.String]; ok {
+			if bookWords[book.Id] == nil {
+				bookWords[book.Id] = map[string]bool{}
+			}
+			if !bookWords[book.Id][word.Id] {
+				bookWords[book.Id][word.Id] = true
+				book.Words++
+			}
+		}
+	}
+
+	return order, rows.Err()
+}
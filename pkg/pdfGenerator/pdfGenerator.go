@@ -1,18 +1,105 @@
 package pdfgenerator
 
 import (
+	"errors"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+	"vacabulary/models"
+
 	"github.com/johnfercher/maroto/pkg/color"
 	"github.com/johnfercher/maroto/pkg/consts"
 	"github.com/johnfercher/maroto/pkg/pdf"
 	"github.com/johnfercher/maroto/pkg/props"
 )
 
+const (
+	LayoutTable      = "table"
+	LayoutFoldIn     = "foldIn"
+	LayoutFlashcards = "flashcards"
+
+	ColumnPartOfSpeech  = "partOfSpeech"
+	ColumnSentence      = "sentence"
+	ColumnTranscription = "transcription"
+
+	OrientationPortrait  = "portrait"
+	OrientationLandscape = "landscape"
+
+	SortByWord         = "word"
+	SortByCreatedAt    = "createdAt"
+	SortByPartOfSpeech = "partOfSpeech"
+	SortByRandom       = "random"
+)
+
+// Options of collection pdf, empty options keep the table with words and translations
+type Options struct {
+	Layout        string   `json:"layout"`
+	Columns       []string `json:"columns"`
+	Orientation   string   `json:"orientation"`
+	SortBy        string   `json:"sortBy"`
+	SortOrder     string   `json:"sortOrder"`
+	PartsOfSpeech []string `json:"partsOfSpeech"`
+}
+
+// Normalize sets default values and checks options
+func (o Options) Normalize() (Options, error) {
+	if o.Layout == "" {
+		o.Layout = LayoutTable
+	}
+	if o.Layout != LayoutTable && o.Layout != LayoutFoldIn && o.Layout != LayoutFlashcards {
+		return o, errors.New("layout must be one of table, foldIn, flashcards")
+	}
+
+	for _, c := range o.Columns {
+		if c != ColumnPartOfSpeech && c != ColumnSentence && c != ColumnTranscription {
+			return o, errors.New("columns must be some of partOfSpeech, sentence, transcription")
+		}
+	}
+
+	if o.Orientation == "" {
+		o.Orientation = OrientationPortrait
+	}
+	if o.Orientation != OrientationPortrait && o.Orientation != OrientationLandscape {
+		return o, errors.New("orientation must be one of portrait, landscape")
+	}
+
+	if o.SortBy != "" && o.SortBy != SortByWord && o.SortBy != SortByCreatedAt && o.SortBy != SortByPartOfSpeech && o.SortBy != SortByRandom {
+		return o, errors.New("sortBy must be one of word, createdAt, partOfSpeech, random")
+	}
+
+	if o.SortOrder == "" {
+		o.SortOrder = models.SortOrderAsc
+	}
+	if o.SortOrder != models.SortOrderAsc && o.SortOrder != models.SortOrderDesc {
+		return o, errors.New("sortOrder must be one of asc, desc")
+	}
+
+	return o, nil
+}
+
+func (o Options) hasColumn(column string) bool {
+	for _, c := range o.Columns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
+
 type PdfGenerator struct {
-	pdf pdf.Maroto
+	pdf     pdf.Maroto
+	options Options
 }
 
-func NewPdfGenerator() PdfGenerator {
-	instance := pdf.NewMaroto(consts.Portrait, consts.A4)
+func NewPdfGenerator(options Options) PdfGenerator {
+	orientation := consts.Portrait
+	if options.Orientation == OrientationLandscape {
+		orientation = consts.Landscape
+	}
+
+	instance := pdf.NewMaroto(orientation, consts.A4)
 
 	instance.AddUTF8Font("CustomArial", consts.Normal, "pkg/pdfGenerator/fonts/arial-unicode-ms.ttf")
 	instance.AddUTF8Font("CustomArial", consts.Italic, "pkg/pdfGenerator/fonts/arial-unicode-ms.ttf")
@@ -20,17 +107,35 @@ func NewPdfGenerator() PdfGenerator {
 	instance.AddUTF8Font("CustomArial", consts.BoldItalic, "pkg/pdfGenerator/fonts/arial-unicode-ms.ttf")
 	instance.SetDefaultFontFamily("CustomArial")
 
-	return PdfGenerator{pdf: instance}
+	return PdfGenerator{pdf: instance, options: options}
 }
 
-func GenerateCollectionPdf(contents [][]string, tableName string) ([]byte, error) {
-	instance := NewPdfGenerator()
+// GenerateCollectionPdf filters and sorts words and prints them with selected layout
+func GenerateCollectionPdf(words []models.Word, tableName string, options Options) ([]byte, error) {
+	options, err := options.Normalize()
+	if err != nil {
+		return nil, err
+	}
 
-	instance.pdf.SetPageMargins(20, 20, 20)
+	instance := NewPdfGenerator(options)
 
-	instance.generateHeader()
+	words = sortWords(filterWords(words, options.PartsOfSpeech), options.SortBy, options.SortOrder)
 
-	instance.generateWordsList(contents, tableName)
+	switch options.Layout {
+	case LayoutFoldIn:
+		instance.pdf.SetPageMargins(20, 20, 20)
+		instance.generateHeader()
+		instance.generateTitle(tableName)
+		instance.generateFoldIn(words)
+	case LayoutFlashcards:
+		// cards use the whole page, so header is not added
+		instance.generateFlashcards(words)
+	default:
+		instance.pdf.SetPageMargins(20, 20, 20)
+		instance.generateHeader()
+		instance.generateTitle(tableName)
+		instance.generateWordsList(words)
+	}
 
 	res, err := instance.pdf.Output()
 	if err != nil {
@@ -40,6 +145,66 @@ func GenerateCollectionPdf(contents [][]string, tableName string) ([]byte, error
 	return res.Bytes(), nil
 }
 
+func filterWords(words []models.Word, partsOfSpeech []string) []models.Word {
+	if len(partsOfSpeech) == 0 {
+		return words
+	}
+
+	allowed := map[string]bool{}
+	for _, p := range partsOfSpeech {
+		allowed[strings.ToLower(strings.TrimSpace(p))] = true
+	}
+
+	filtered := []models.Word{}
+	for _, w := range words {
+		if allowed[strings.ToLower(strings.TrimSpace(w.PartOfSpeech))] {
+			filtered = append(filtered, w)
+		}
+	}
+
+	return filtered
+}
+
+func sortWords(words []models.Word, sortBy, sortOrder string) []models.Word {
+	sorted := append([]models.Word{}, words...)
+
+	var less func(a, b models.Word) bool
+	switch sortBy {
+	case SortByWord:
+		less = func(a, b models.Word) bool {
+			return strings.ToLower(a.Word) < strings.ToLower(b.Word)
+		}
+	case SortByCreatedAt:
+		less = func(a, b models.Word) bool {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+	case SortByPartOfSpeech:
+		less = func(a, b models.Word) bool {
+			if a.PartOfSpeech != b.PartOfSpeech {
+				return strings.ToLower(a.PartOfSpeech) < strings.ToLower(b.PartOfSpeech)
+			}
+			return strings.ToLower(a.Word) < strings.ToLower(b.Word)
+		}
+	case SortByRandom:
+		r := rand.New(rand.NewSource(time.Now().UnixNano()))
+		r.Shuffle(len(sorted), func(i, j int) {
+			sorted[i], sorted[j] = sorted[j], sorted[i]
+		})
+		return sorted
+	default:
+		return sorted
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sortOrder == models.SortOrderDesc {
+			return less(sorted[j], sorted[i])
+		}
+		return less(sorted[i], sorted[j])
+	})
+
+	return sorted
+}
+
 func (p *PdfGenerator) generateHeader() {
 	p.pdf.RegisterHeader(func() {
 		p.pdf.Row(10, func() {
@@ -50,14 +215,7 @@ func (p *PdfGenerator) generateHeader() {
 	})
 }
 
-func (p *PdfGenerator) generateWordsList(contents [][]string, tableName string) {
-	tableHeadings := []string{"Word", "Translation"}
-	lightPurpleColor := color.Color{
-		Red:   210,
-		Green: 200,
-		Blue:  230,
-	}
-
+func (p *PdfGenerator) generateTitle(tableName string) {
 	p.pdf.SetBackgroundColor(color.Color{
 		Red:   3,
 		Green: 166,
@@ -78,15 +236,69 @@ func (p *PdfGenerator) generateWordsList(contents [][]string, tableName string)
 	})
 
 	p.pdf.SetBackgroundColor(color.NewWhite())
+}
+
+// tableColumn is the column of the table with its width in the grid of 12
+type tableColumn struct {
+	heading string
+	weight  int
+	value   func(w models.Word) string
+}
+
+func (p *PdfGenerator) tableColumns() []tableColumn {
+	columns := []tableColumn{{heading: "Word", weight: 3, value: func(w models.Word) string { return w.Word }}}
+
+	if p.options.hasColumn(ColumnTranscription) {
+		columns = append(columns, tableColumn{heading: "Transcription", weight: 2, value: func(w models.Word) string { return w.Transcription }})
+	}
+	if p.options.hasColumn(ColumnPartOfSpeech) {
+		columns = append(columns, tableColumn{heading: "Part of speech", weight: 2, value: func(w models.Word) string { return w.PartOfSpeech }})
+	}
+
+	columns = append(columns, tableColumn{heading: "Translation", weight: 3, value: func(w models.Word) string { return w.Translation }})
+
+	if p.options.hasColumn(ColumnSentence) {
+		columns = append(columns, tableColumn{heading: "Sentence", weight: 4, value: func(w models.Word) string { return w.Scentance }})
+	}
+
+	return columns
+}
+
+func (p *PdfGenerator) generateWordsList(words []models.Word) {
+	columns := p.tableColumns()
+
+	tableHeadings := []string{}
+	weights := []int{}
+	for _, c := range columns {
+		tableHeadings = append(tableHeadings, c.heading)
+		weights = append(weights, c.weight)
+	}
+
+	contents := [][]string{}
+	for _, w := range words {
+		row := []string{}
+		for _, c := range columns {
+			row = append(row, c.value(w))
+		}
+		contents = append(contents, row)
+	}
+
+	lightPurpleColor := color.Color{
+		Red:   210,
+		Green: 200,
+		Blue:  230,
+	}
+
+	gridSizes := gridSizes(weights)
 
 	p.pdf.TableList(tableHeadings, contents, props.TableList{
 		HeaderProp: props.TableListContent{
 			Size:      12,
-			GridSizes: []uint{6, 6},
+			GridSizes: gridSizes,
 		},
 		ContentProp: props.TableListContent{
 			Size:      12,
-			GridSizes: []uint{6, 6},
+			GridSizes: gridSizes,
 		},
 		Align:                consts.Left,
 		AlternatedBackground: &lightPurpleColor,
@@ -94,3 +306,181 @@ func (p *PdfGenerator) generateWordsList(contents [][]string, tableName string)
 		Line:                 false,
 	})
 }
+
+// gridSizes splits 12 grid columns by weights with the largest remainder method
+func gridSizes(weights []int) []uint {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+
+	sizes := make([]uint, len(weights))
+	remainders := make([]int, len(weights))
+	used := 0
+	for i, w := range weights {
+		sizes[i] = uint(12 * w / total)
+		remainders[i] = 12 * w % total
+		used += int(sizes[i])
+	}
+
+	for ; used < 12; used++ {
+		largest := 0
+		for i := range remainders {
+			if remainders[i] > remainders[largest] {
+				largest = i
+			}
+		}
+		sizes[largest]++
+		remainders[largest] = -1
+	}
+
+	return sizes
+}
+
+// frontLines returns text of the word side, backLines returns text of the translation side
+func (p *PdfGenerator) frontLines(w models.Word) []string {
+	lines := []string{w.Word}
+	if p.options.hasColumn(ColumnTranscription) && w.Transcription != "" {
+		lines = append(lines, "["+strings.Trim(w.Transcription, "[]/")+"]")
+	}
+	if p.options.hasColumn(ColumnPartOfSpeech) && w.PartOfSpeech != "" {
+		lines = append(lines, w.PartOfSpeech)
+	}
+
+	return lines
+}
+
+func (p *PdfGenerator) backLines(w models.Word) []string {
+	lines := []string{w.Translation}
+	if p.options.hasColumn(ColumnSentence) && w.Scentance != "" {
+		lines = append(lines, w.Scentance)
+	}
+
+	return lines
+}
+
+// generateFoldIn prints words on the left half and translations on the right half,
+// the page is folded along the middle to hide translations for self-test
+func (p *PdfGenerator) generateFoldIn(words []models.Word) {
+	p.pdf.Row(8, func() {
+		p.pdf.Col(12, func() {
+			p.pdf.Text("Fold the page in half along the dashed line to hide translations", props.Text{
+				Size:  9,
+				Style: consts.Italic,
+				Align: consts.Center,
+			})
+		})
+	})
+
+	rowHeight := 7.0
+	if p.options.hasColumn(ColumnSentence) || p.options.hasColumn(ColumnTranscription) || p.options.hasColumn(ColumnPartOfSpeech) {
+		rowHeight = 13
+	}
+
+	for _, w := range words {
+		front := p.frontLines(w)
+		back := p.backLines(w)
+
+		p.pdf.Row(rowHeight, func() {
+			p.pdf.Col(5, func() {
+				p.cellLines(front, consts.Left)
+			})
+			p.pdf.Col(2, func() {
+				p.pdf.Text("- - - - - -", props.Text{Top: 1, Size: 10, Align: consts.Center, Color: color.Color{Red: 150, Green: 150, Blue: 150}})
+			})
+			p.pdf.Col(5, func() {
+				p.cellLines(back, consts.Left)
+			})
+		})
+	}
+}
+
+// cellLines prints the first line bold and other lines smaller below it
+func (p *PdfGenerator) cellLines(lines []string, align consts.Align) {
+	top := 1.0
+	for i, line := range lines {
+		prop := props.Text{Top: top, Size: 9, Align: align}
+		if i == 0 {
+			prop.Size = 12
+			prop.Style = consts.Bold
+		}
+		p.pdf.Text(line, prop)
+		top += 5
+	}
+}
+
+// generateFlashcards prints cards with words on odd pages and translations on even pages,
+// columns of the back side are mirrored so both sides match with two-sided printing
+func (p *PdfGenerator) generateFlashcards(words []models.Word) {
+	cols, rows := 2, 5
+	if p.options.Orientation == OrientationLandscape {
+		cols, rows = 3, 3
+	}
+	perPage := cols * rows
+
+	_, pageHeight := p.pdf.GetPageSize()
+	_, top, _, bottom := p.pdf.GetPageMargins()
+	cardHeight := float64(int((pageHeight - top - bottom) / float64(rows)))
+	colWidth := uint(12 / cols)
+
+	p.pdf.SetBorder(true)
+
+	for from := 0; from < len(words); from += perPage {
+		to := from + perPage
+		if to > len(words) {
+			to = len(words)
+		}
+		sheet := words[from:to]
+
+		if from > 0 {
+			p.pdf.AddPage()
+		}
+		p.generateCardsSide(sheet, cols, colWidth, cardHeight, false)
+
+		p.pdf.AddPage()
+		p.generateCardsSide(sheet, cols, colWidth, cardHeight, true)
+	}
+}
+
+func (p *PdfGenerator) generateCardsSide(words []models.Word, cols int, colWidth uint, cardHeight float64, back bool) {
+	for from := 0; from < len(words); from += cols {
+		p.pdf.Row(cardHeight, func() {
+			for i := 0; i < cols; i++ {
+				index := from + i
+				if back {
+					index = from + cols - 1 - i
+				}
+
+				if index >= len(words) {
+					p.pdf.ColSpace(colWidth)
+					continue
+				}
+
+				lines := p.frontLines(words[index])
+				if back {
+					lines = p.backLines(words[index])
+				}
+
+				p.pdf.Col(colWidth, func() {
+					p.cardLines(lines, cardHeight)
+				})
+			}
+		})
+	}
+}
+
+// cardLines prints lines in the middle of the card
+func (p *PdfGenerator) cardLines(lines []string, cardHeight float64) {
+	top := cardHeight/2 - float64(len(lines)-1)*3.5 - 3
+	for i, line := range lines {
+		prop := props.Text{Top: top, Size: 10, Align: consts.Center}
+		if i == 0 {
+			prop.Size = 16
+			prop.Style = consts.Bold
+			top += 9
+		} else {
+			top += 6
+		}
+		p.pdf.Text(line, prop)
+	}
+}
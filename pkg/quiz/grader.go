@@ -0,0 +1,168 @@
+package quiz
+
+import (
+	"strings"
+	"unicode"
+	"vacabulary/models"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// alternative translations are usually separated by these symbols
+const variantsSeparators = ",;/"
+
+// Grade checks the answer for the word and explains the result
+func Grade(word models.Word, answer models.QuizAnswer) models.QuizQuestionResult {
+	result := models.QuizQuestionResult{
+		WordId:        word.Id,
+		Mode:          answer.Mode,
+		Prompt:        word.Word,
+		Answer:        answer.Answer,
+		CorrectAnswer: word.Translation,
+	}
+
+	if answer.Mode == models.QuizModeReverse {
+		result.Prompt = word.Translation
+		result.CorrectAnswer = word.Word
+	}
+
+	if strings.TrimSpace(answer.Answer) == "" {
+		result.Feedback = "no answer"
+		return result
+	}
+
+	// any of word translations is accepted
+	alternatives := word.Translations
+	if answer.Mode == models.QuizModeReverse {
+		alternatives = nil
+	}
+
+	switch answer.Mode {
+	case models.QuizModeMultipleChoice:
+		result.Correct = normalize(answer.Answer) == normalize(result.CorrectAnswer)
+		for _, alternative := range alternatives {
+			if normalize(answer.Answer) == normalize(alternative) {
+				result.Correct = true
+			}
+		}
+
+		if result.Correct {
+			result.Feedback = "correct"
+		} else {
+			result.Feedback = "wrong option"
+		}
+	default:
+		result.Correct, result.Feedback = checkTyped(answer.Answer, result.CorrectAnswer, alternatives)
+	}
+
+	return result
+}
+
+func checkTyped(answer, expected string, alternatives []string) (bool, string) {
+	normalizedAnswer := normalize(answer)
+
+	var variants []string
+	for _, value := range append([]string{expected}, alternatives...) {
+		variants = append(variants, strings.FieldsFunc(value, func(r rune) bool {
+			return strings.ContainsRune(variantsSeparators, r)
+		})...)
+		variants = append(variants, value)
+	}
+
+	bestDistance := -1
+	for _, variant := range variants {
+		normalizedVariant := normalize(variant)
+		if normalizedVariant == "" {
+			continue
+		}
+
+		if strings.EqualFold(strings.TrimSpace(answer), strings.TrimSpace(variant)) {
+			return true, "correct"
+		}
+
+		if normalizedAnswer == normalizedVariant {
+			return true, "correct, check accents and punctuation"
+		}
+
+		distance := levenshtein(normalizedAnswer, normalizedVariant)
+		if distance <= typoTolerance(normalizedVariant) && (bestDistance == -1 || distance < bestDistance) {
+			bestDistance = distance
+		}
+	}
+
+	if bestDistance != -1 {
+		return true, "correct, but with a typo"
+	}
+
+	return false, "wrong answer"
+}
+
+// typoTolerance returns count of allowed mistakes for the word length
+func typoTolerance(word string) int {
+	length := len([]rune(word))
+
+	switch {
+	case length <= 3:
+		return 0
+	case length <= 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// normalize lowercases text, removes diacritics, punctuation and extra spaces
+func normalize(text string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	withoutDiacritics, _, err := transform.String(t, text)
+	if err != nil {
+		withoutDiacritics = text
+	}
+
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, withoutDiacritics)
+
+	return strings.Join(strings.Fields(cleaned), " ")
+}
+
+func levenshtein(a, b string) int {
+	first, second := []rune(a), []rune(b)
+
+	previous := make([]int, len(second)+1)
+	current := make([]int, len(second)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(first); i++ {
+		current[0] = i
+		for j := 1; j <= len(second); j++ {
+			cost := 1
+			if first[i-1] == second[j-1] {
+				cost = 0
+			}
+
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(second)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+
+	return min
+}
@@ -0,0 +1,125 @@
+package quiz
+
+import (
+	"testing"
+	"vacabulary/models"
+)
+
+func TestGrade(t *testing.T) {
+	word := models.Word{
+		Id:           "1",
+		Word:         "café",
+		Translation:  "кав'ярня",
+		Translations: []string{"кав'ярня", "кафе"},
+	}
+	longWord := models.Word{
+		Id:          "2",
+		Word:        "understanding",
+		Translation: "розуміння, тяма",
+	}
+
+	tests := []struct {
+		name     string
+		word     models.Word
+		answer   models.QuizAnswer
+		correct  bool
+		feedback string
+	}{
+		{
+			name:     "empty answer",
+			word:     word,
+			answer:   models.QuizAnswer{Mode: models.QuizModeTyping, Answer: "  "},
+			feedback: "no answer",
+		},
+		{
+			name:     "typed main translation",
+			word:     word,
+			answer:   models.QuizAnswer{Mode: models.QuizModeTyping, Answer: "Кав'ярня"},
+			correct:  true,
+			feedback: "correct",
+		},
+		{
+			name:     "typed alternative translation",
+			word:     word,
+			answer:   models.QuizAnswer{Mode: models.QuizModeTyping, Answer: "кафе"},
+			correct:  true,
+			feedback: "correct",
+		},
+		{
+			name:     "typed without punctuation",
+			word:     word,
+			answer:   models.QuizAnswer{Mode: models.QuizModeTyping, Answer: "кавярня"},
+			correct:  true,
+			feedback: "correct, check accents and punctuation",
+		},
+		{
+			name:     "typed variant of separated translation",
+			word:     longWord,
+			answer:   models.QuizAnswer{Mode: models.QuizModeTyping, Answer: "тяма"},
+			correct:  true,
+			feedback: "correct",
+		},
+		{
+			name:     "typed with a typo",
+			word:     longWord,
+			answer:   models.QuizAnswer{Mode: models.QuizModeTyping, Answer: "розумiння"},
+			correct:  true,
+			feedback: "correct, but with a typo",
+		},
+		{
+			name:     "short variant doesn't allow typos",
+			word:     models.Word{Word: "cat", Translation: "кіт"},
+			answer:   models.QuizAnswer{Mode: models.QuizModeTyping, Answer: "кит"},
+			feedback: "wrong answer",
+		},
+		{
+			name:     "reverse without accents",
+			word:     word,
+			answer:   models.QuizAnswer{Mode: models.QuizModeReverse, Answer: "cafe"},
+			correct:  true,
+			feedback: "correct, check accents and punctuation",
+		},
+		{
+			name:     "reverse doesn't accept translations",
+			word:     word,
+			answer:   models.QuizAnswer{Mode: models.QuizModeReverse, Answer: "кафе"},
+			feedback: "wrong answer",
+		},
+		{
+			name:     "chosen option",
+			word:     word,
+			answer:   models.QuizAnswer{Mode: models.QuizModeMultipleChoice, Answer: "кафе"},
+			correct:  true,
+			feedback: "correct",
+		},
+		{
+			name:     "option with a typo is wrong",
+			word:     longWord,
+			answer:   models.QuizAnswer{Mode: models.QuizModeMultipleChoice, Answer: "розумiння, тяма"},
+			feedback: "wrong option",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Grade(tt.word, tt.answer)
+
+			if result.Correct != tt.correct {
+				t.Errorf("correct: expected %v, got %v", tt.correct, result.Correct)
+			}
+			if result.Feedback != tt.feedback {
+				t.Errorf("feedback: expected %q, got %q", tt.feedback, result.Feedback)
+			}
+		})
+	}
+}
+
+func TestGradeReversePrompt(t *testing.T) {
+	word := models.Word{Id: "1", Word: "dog", Translation: "пес"}
+
+	result := Grade(word, models.QuizAnswer{Mode: models.QuizModeReverse, Answer: "dog"})
+
+	if result.Prompt != "пес" || result.CorrectAnswer != "dog" {
+		t.Errorf("expected translation prompt and word answer, got %q and %q", result.Prompt, result.CorrectAnswer)
+	}
+}
@@ -0,0 +1,131 @@
+package quiz
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+	"vacabulary/models"
+)
+
+const (
+	multipleChoiceOptions = 4
+)
+
+var (
+	ErrNotEnoughWords = errors.New("not enough words in collection for quiz")
+)
+
+// QuizGenerator is shared by requests, random is not safe for concurrent use, so it is guarded by mu
+type QuizGenerator struct {
+	mu     *sync.Mutex
+	random *rand.Rand
+}
+
+func NewQuizGenerator() QuizGenerator {
+	return QuizGenerator{
+		mu:     &sync.Mutex{},
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Generate builds quiz with up to size questions from collection words
+func (g *QuizGenerator) Generate(words []models.Word, mode models.QuizMode, size int) ([]models.QuizQuestion, error) {
+	if len(words) == 0 {
+		return nil, ErrNotEnoughWords
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	shuffled := make([]models.Word, len(words))
+	copy(shuffled, words)
+	g.random.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	if size <= 0 || size > len(shuffled) {
+		size = len(shuffled)
+	}
+
+	questions := []models.QuizQuestion{}
+	for _, word := range shuffled[:size] {
+		questionMode := mode
+		if mode == models.QuizModeMixed {
+			questionMode = g.randomMode(len(words))
+		}
+
+		// multiple choice needs at least one distractor
+		if questionMode == models.QuizModeMultipleChoice && len(words) < 2 {
+			questionMode = models.QuizModeTyping
+		}
+
+		question := models.QuizQuestion{
+			WordId: word.Id,
+			Mode:   questionMode,
+			Prompt: word.Word,
+		}
+
+		switch questionMode {
+		case models.QuizModeMultipleChoice:
+			question.Options = g.buildOptions(word, words)
+		case models.QuizModeReverse:
+			question.Prompt = word.Translation
+		}
+
+		questions = append(questions, question)
+	}
+
+	return questions, nil
+}
+
+func (g *QuizGenerator) randomMode(wordsCount int) models.QuizMode {
+	modes := []models.QuizMode{models.QuizModeTyping, models.QuizModeReverse}
+	if wordsCount > 1 {
+		modes = append(modes, models.QuizModeMultipleChoice)
+	}
+
+	return modes[g.random.Intn(len(modes))]
+}
+
+// buildOptions returns shuffled translations: the correct one and distractors,
+// which are taken from words with same part of speech first
+func (g *QuizGenerator) buildOptions(word models.Word, words []models.Word) []string {
+	var samePart, otherPart []string
+	seen := map[string]bool{normalize(word.Translation): true}
+
+	for _, w := range words {
+		key := normalize(w.Translation)
+		if w.Id == word.Id || key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if w.PartOfSpeech == word.PartOfSpeech {
+			samePart = append(samePart, w.Translation)
+		} else {
+			otherPart = append(otherPart, w.Translation)
+		}
+	}
+
+	g.random.Shuffle(len(samePart), func(i, j int) {
+		samePart[i], samePart[j] = samePart[j], samePart[i]
+	})
+	g.random.Shuffle(len(otherPart), func(i, j int) {
+		otherPart[i], otherPart[j] = otherPart[j], otherPart[i]
+	})
+
+	options := []string{word.Translation}
+	for _, distractor := range append(samePart, otherPart...) {
+		if len(options) == multipleChoiceOptions {
+			break
+		}
+		options = append(options, distractor)
+	}
+
+	g.random.Shuffle(len(options), func(i, j int) {
+		options[i], options[j] = options[j], options[i]
+	})
+
+	return options
+}
@@ -0,0 +1,45 @@
+package quizlet
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"vacabulary/pkg/wordimport"
+)
+
+// Card is the term and definition of quizlet set
+type Card struct {
+	Term       string
+	Definition string
+}
+
+// Export writes cards in quizlet format: tab between term and definition,
+// new line between cards
+func Export(cards []Card) []byte {
+	buf := &bytes.Buffer{}
+	for _, c := range cards {
+		buf.WriteString(clean(c.Term))
+		buf.WriteString("\t")
+		buf.WriteString(clean(c.Definition))
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes()
+}
+
+// Import reads cards exported from quizlet with default separators
+func Import(r io.Reader) ([]wordimport.Row, error) {
+	return wordimport.Parse(r, wordimport.FormatTSV, false, wordimport.Mapping{
+		Word:        "1",
+		Translation: "2",
+	})
+}
+
+// clean removes separators from the value, they can't be escaped in quizlet format
+func clean(value string) string {
+	value = strings.ReplaceAll(value, "\t", " ")
+	value = strings.ReplaceAll(value, "\r\n", " ")
+	value = strings.ReplaceAll(value, "\n", " ")
+
+	return strings.TrimSpace(value)
+}
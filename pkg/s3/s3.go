@@ -2,56 +2,95 @@ package s3
 
 import (
 	"bytes"
+	"fmt"
+	"path"
+	"sync"
+	"time"
 	"vacabulary/config"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3"
 )
 
+// S3Manager stores files in s3 bucket, custom endpoint is used for s3 compatible storage like MinIO
 type S3Manager struct {
 	awsSession *session.Session
+	once       sync.Once
 	config     config.AWSConfig
+	storage    config.StorageConfig
 }
 
-func (s *S3Manager) StorePdfFile(pathToFile string, file *bytes.Reader) (string, error) {
-	sess := s._getAwsSession()
+func (s *S3Manager) Store(key string, data []byte, contentType string) error {
+	client := s3.New(s._getAwsSession())
 
-	s3 := s3manager.NewUploader(sess)
+	_, err := client.PutObject(&s3.PutObjectInput{
+		Bucket:             aws.String(s.storage.GetBucket()),
+		Key:                aws.String(key),
+		Body:               bytes.NewReader(data),
+		ContentType:        aws.String(contentType),
+		ContentDisposition: aws.String(fmt.Sprintf("attachment; filename=\"%s\"", path.Base(key))),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// URL returns presigned url, files are private
+func (s *S3Manager) URL(key string, ttl time.Duration) (string, error) {
+	client := s3.New(s._getAwsSession())
+
+	req, _ := client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.storage.GetBucket()),
+		Key:    aws.String(key),
+	})
 
-	res, err := s3.Upload(&s3manager.UploadInput{
-		Bucket: aws.String("collections-words"),
-		Key:    aws.String(pathToFile),
-		ACL:    aws.String("public-read"),
-		Body:   file,
+	return req.Presign(ttl)
+}
+
+func (s *S3Manager) Delete(key string) error {
+	client := s3.New(s._getAwsSession())
+
+	_, err := client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.storage.GetBucket()),
+		Key:    aws.String(key),
 	})
 	if err != nil {
-		return "", nil
+		return err
 	}
 
-	return res.Location, nil
+	return nil
 }
 
 func (s *S3Manager) _getAwsSession() *session.Session {
-	if s.awsSession == nil {
-		s._setAwsSession()
-	}
+	// session is shared by requests and export worker
+	s.once.Do(s._setAwsSession)
 
 	return s.awsSession
 }
 
 func (s *S3Manager) _setAwsSession() {
-	sess := session.Must(session.NewSession(&aws.Config{
+	awsConfig := &aws.Config{
 		Region:      &s.config.Region,
 		Credentials: credentials.NewStaticCredentials(s.config.AccessId, s.config.Secret, ""),
-	}))
+	}
+
+	if s.storage.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(s.storage.Endpoint)
+		awsConfig.S3ForcePathStyle = aws.Bool(true)
+	}
+
+	sess := session.Must(session.NewSession(awsConfig))
 
 	s.awsSession = sess
 }
 
-func NewS3Manager(config config.AWSConfig) S3Manager {
-	return S3Manager{
-		config: config,
+func NewS3Manager(config config.AWSConfig, storage config.StorageConfig) *S3Manager {
+	return &S3Manager{
+		config:  config,
+		storage: storage,
 	}
 }
@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"errors"
+	"time"
+	"vacabulary/models"
+)
+
+const (
+	minLeitnerBoxes = 2
+	maxLeitnerBoxes = 10
+)
+
+var (
+	// DefaultLeitnerIntervals are review intervals in days for every box
+	DefaultLeitnerIntervals = []uint64{1, 2, 4, 8, 16}
+
+	ErrInvalidLeitnerIntervals = errors.New("leitner intervals must contain from 2 to 10 positive non decreasing values")
+)
+
+// LeitnerScheduler moves words between boxes, every box has its own review interval
+type LeitnerScheduler struct {
+	intervals []uint64
+}
+
+func NewLeitnerScheduler(intervals []uint64) (LeitnerScheduler, error) {
+	if len(intervals) == 0 {
+		intervals = DefaultLeitnerIntervals
+	}
+
+	err := ValidateLeitnerIntervals(intervals)
+	if err != nil {
+		return LeitnerScheduler{}, err
+	}
+
+	return LeitnerScheduler{intervals: intervals}, nil
+}
+
+func ValidateLeitnerIntervals(intervals []uint64) error {
+	if len(intervals) < minLeitnerBoxes || len(intervals) > maxLeitnerBoxes {
+		return ErrInvalidLeitnerIntervals
+	}
+
+	for i, interval := range intervals {
+		if interval == 0 || (i > 0 && interval < intervals[i-1]) {
+			return ErrInvalidLeitnerIntervals
+		}
+	}
+
+	return nil
+}
+
+func (s *LeitnerScheduler) Schedule(progress models.WordProgress, grade models.ReviewGrade, now time.Time) (models.WordProgress, error) {
+	// boxes are numbered from 1, never reviewed words are in the first box
+	box := progress.Box
+	if box == 0 {
+		box = 1
+	}
+
+	repetitions := progress.Repetitions
+
+	switch grade {
+	case models.ReviewGradeAgain:
+		box = 1
+		repetitions = 0
+	case models.ReviewGradeHard:
+		repetitions++
+	case models.ReviewGradeGood:
+		box++
+		repetitions++
+	case models.ReviewGradeEasy:
+		box += 2
+		repetitions++
+	default:
+		return progress, errUnknownGrade
+	}
+
+	lastBox := uint64(len(s.intervals))
+	if box > lastBox {
+		box = lastBox
+	}
+
+	interval := s.intervals[box-1]
+	dueDate := now.Add(time.Duration(interval) * day)
+
+	scheduled := models.WordProgress{
+		EaseFactor:     progress.EaseFactor,
+		Interval:       interval,
+		Repetitions:    repetitions,
+		Box:            box,
+		DueDate:        &dueDate,
+		LastReviewedAt: &now,
+	}
+	scheduled.Mastery = MasteryLevel(scheduled)
+
+	return scheduled, nil
+}
@@ -0,0 +1,24 @@
+package scheduler
+
+import "vacabulary/models"
+
+// intervals in days starting from which word gets the level
+const (
+	KnownInterval    = 7
+	MasteredInterval = 21
+)
+
+// MasteryLevel derives mastery from review progress, so it
+// doesn't depend on algorithm used by the collection
+func MasteryLevel(progress models.WordProgress) models.MasteryLevel {
+	switch {
+	case progress.LastReviewedAt == nil:
+		return models.MasteryLevelNew
+	case progress.Interval >= MasteredInterval:
+		return models.MasteryLevelMastered
+	case progress.Interval >= KnownInterval:
+		return models.MasteryLevelKnown
+	default:
+		return models.MasteryLevelLearning
+	}
+}
@@ -0,0 +1,33 @@
+package scheduler
+
+import (
+	"errors"
+	"time"
+	"vacabulary/models"
+)
+
+var (
+	ErrUnknownScheduler = errors.New("unknown scheduler type")
+)
+
+// Scheduler calculates next review of the word after the answer
+type Scheduler interface {
+	Schedule(progress models.WordProgress, grade models.ReviewGrade, now time.Time) (models.WordProgress, error)
+}
+
+// NewScheduler returns scheduler configured for the collection
+func NewScheduler(settings models.SchedulerSettings) (Scheduler, error) {
+	switch settings.Type {
+	case models.SchedulerTypeSM2, "":
+		sm2 := NewSM2Scheduler()
+		return &sm2, nil
+	case models.SchedulerTypeLeitner:
+		leitner, err := NewLeitnerScheduler(settings.LeitnerIntervals)
+		if err != nil {
+			return nil, err
+		}
+		return &leitner, nil
+	}
+
+	return nil, ErrUnknownScheduler
+}
@@ -0,0 +1,244 @@
+package scheduler
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+	"vacabulary/models"
+)
+
+var reviewedAt = time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+
+func TestSM2Schedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress models.WordProgress
+		grade    models.ReviewGrade
+		want     models.WordProgress
+	}{
+		{
+			name:     "new word answered good",
+			progress: models.WordProgress{},
+			grade:    models.ReviewGradeGood,
+			want:     models.WordProgress{EaseFactor: 2.5, Interval: 1, Repetitions: 1, Mastery: models.MasteryLevelLearning},
+		},
+		{
+			name:     "second good answer",
+			progress: models.WordProgress{EaseFactor: 2.5, Interval: 1, Repetitions: 1},
+			grade:    models.ReviewGradeGood,
+			want:     models.WordProgress{EaseFactor: 2.5, Interval: 6, Repetitions: 2, Mastery: models.MasteryLevelLearning},
+		},
+		{
+			name:     "interval is multiplied by ease factor",
+			progress: models.WordProgress{EaseFactor: 2.5, Interval: 6, Repetitions: 2},
+			grade:    models.ReviewGradeGood,
+			want:     models.WordProgress{EaseFactor: 2.5, Interval: 15, Repetitions: 3, Mastery: models.MasteryLevelKnown},
+		},
+		{
+			name:     "easy answer gets bonus",
+			progress: models.WordProgress{EaseFactor: 2.5, Interval: 6, Repetitions: 2},
+			grade:    models.ReviewGradeEasy,
+			want:     models.WordProgress{EaseFactor: 2.6, Interval: 21, Repetitions: 3, Mastery: models.MasteryLevelMastered},
+		},
+		{
+			name:     "failed answer restarts learning",
+			progress: models.WordProgress{EaseFactor: 2.5, Interval: 15, Repetitions: 3},
+			grade:    models.ReviewGradeAgain,
+			want:     models.WordProgress{EaseFactor: 1.96, Interval: 1, Repetitions: 0, Mastery: models.MasteryLevelLearning},
+		},
+		{
+			name:     "ease factor doesn't go below minimum",
+			progress: models.WordProgress{EaseFactor: minEaseFactor, Interval: 1, Repetitions: 1},
+			grade:    models.ReviewGradeHard,
+			want:     models.WordProgress{EaseFactor: minEaseFactor, Interval: 6, Repetitions: 2, Mastery: models.MasteryLevelLearning},
+		},
+		{
+			name:     "box is kept",
+			progress: models.WordProgress{EaseFactor: 2.5, Box: 3},
+			grade:    models.ReviewGradeGood,
+			want:     models.WordProgress{EaseFactor: 2.5, Interval: 1, Repetitions: 1, Box: 3, Mastery: models.MasteryLevelLearning},
+		},
+	}
+
+	s := NewSM2Scheduler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Schedule(tt.progress, tt.grade, reviewedAt)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			assertProgress(t, got, tt.want)
+		})
+	}
+}
+
+func TestSM2ScheduleUnknownGrade(t *testing.T) {
+	s := NewSM2Scheduler()
+	progress := models.WordProgress{Interval: 6, Repetitions: 2}
+
+	got, err := s.Schedule(progress, models.ReviewGrade("perfect"), reviewedAt)
+	if !errors.Is(err, errUnknownGrade) {
+		t.Fatalf("expected unknown grade error, got %v", err)
+	}
+
+	if got.Interval != progress.Interval || got.Repetitions != progress.Repetitions {
+		t.Errorf("progress is changed on error: %+v", got)
+	}
+}
+
+func TestLeitnerSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress models.WordProgress
+		grade    models.ReviewGrade
+		want     models.WordProgress
+	}{
+		{
+			name:     "new word moves to the second box",
+			progress: models.WordProgress{},
+			grade:    models.ReviewGradeGood,
+			want:     models.WordProgress{Interval: 2, Repetitions: 1, Box: 2, Mastery: models.MasteryLevelLearning},
+		},
+		{
+			name:     "hard answer keeps the box",
+			progress: models.WordProgress{Interval: 2, Repetitions: 1, Box: 2},
+			grade:    models.ReviewGradeHard,
+			want:     models.WordProgress{Interval: 2, Repetitions: 2, Box: 2, Mastery: models.MasteryLevelLearning},
+		},
+		{
+			name:     "easy answer skips a box",
+			progress: models.WordProgress{Interval: 2, Repetitions: 1, Box: 2},
+			grade:    models.ReviewGradeEasy,
+			want:     models.WordProgress{Interval: 8, Repetitions: 2, Box: 4, Mastery: models.MasteryLevelKnown},
+		},
+		{
+			name:     "box is limited by the last one",
+			progress: models.WordProgress{Interval: 8, Repetitions: 3, Box: 4},
+			grade:    models.ReviewGradeEasy,
+			want:     models.WordProgress{Interval: 16, Repetitions: 4, Box: 5, Mastery: models.MasteryLevelKnown},
+		},
+		{
+			name:     "failed answer returns to the first box",
+			progress: models.WordProgress{Interval: 8, Repetitions: 3, Box: 4},
+			grade:    models.ReviewGradeAgain,
+			want:     models.WordProgress{Interval: 1, Repetitions: 0, Box: 1, Mastery: models.MasteryLevelLearning},
+		},
+	}
+
+	s, err := NewLeitnerScheduler(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Schedule(tt.progress, tt.grade, reviewedAt)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			assertProgress(t, got, tt.want)
+		})
+	}
+}
+
+func TestValidateLeitnerIntervals(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []uint64
+		valid     bool
+	}{
+		{name: "default", intervals: DefaultLeitnerIntervals, valid: true},
+		{name: "equal intervals", intervals: []uint64{1, 1}, valid: true},
+		{name: "one box", intervals: []uint64{1}},
+		{name: "too many boxes", intervals: []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}},
+		{name: "zero interval", intervals: []uint64{0, 1}},
+		{name: "decreasing intervals", intervals: []uint64{2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLeitnerIntervals(tt.intervals)
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidLeitnerIntervals) {
+				t.Errorf("expected invalid intervals error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewScheduler(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings models.SchedulerSettings
+		err      error
+	}{
+		{name: "sm2 by default", settings: models.SchedulerSettings{}},
+		{name: "sm2", settings: models.SchedulerSettings{Type: models.SchedulerTypeSM2}},
+		{name: "leitner", settings: models.SchedulerSettings{Type: models.SchedulerTypeLeitner, LeitnerIntervals: []uint64{1, 3}}},
+		{name: "leitner with invalid intervals", settings: models.SchedulerSettings{Type: models.SchedulerTypeLeitner, LeitnerIntervals: []uint64{3}}, err: ErrInvalidLeitnerIntervals},
+		{name: "unknown", settings: models.SchedulerSettings{Type: "anki"}, err: ErrUnknownScheduler},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewScheduler(tt.settings)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestMasteryLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress models.WordProgress
+		want     models.MasteryLevel
+	}{
+		{name: "never reviewed", progress: models.WordProgress{Interval: MasteredInterval}, want: models.MasteryLevelNew},
+		{name: "learning", progress: models.WordProgress{Interval: KnownInterval - 1, LastReviewedAt: &reviewedAt}, want: models.MasteryLevelLearning},
+		{name: "known", progress: models.WordProgress{Interval: KnownInterval, LastReviewedAt: &reviewedAt}, want: models.MasteryLevelKnown},
+		{name: "mastered", progress: models.WordProgress{Interval: MasteredInterval, LastReviewedAt: &reviewedAt}, want: models.MasteryLevelMastered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MasteryLevel(tt.progress); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func assertProgress(t *testing.T, got, want models.WordProgress) {
+	t.Helper()
+
+	if math.Abs(got.EaseFactor-want.EaseFactor) > 1e-9 {
+		t.Errorf("ease factor: expected %v, got %v", want.EaseFactor, got.EaseFactor)
+	}
+	if got.Interval != want.Interval {
+		t.Errorf("interval: expected %d, got %d", want.Interval, got.Interval)
+	}
+	if got.Repetitions != want.Repetitions {
+		t.Errorf("repetitions: expected %d, got %d", want.Repetitions, got.Repetitions)
+	}
+	if got.Box != want.Box {
+		t.Errorf("box: expected %d, got %d", want.Box, got.Box)
+	}
+	if got.Mastery != want.Mastery {
+		t.Errorf("mastery: expected %s, got %s", want.Mastery, got.Mastery)
+	}
+
+	dueDate := reviewedAt.Add(time.Duration(want.Interval) * day)
+	if got.DueDate == nil || !got.DueDate.Equal(dueDate) {
+		t.Errorf("due date: expected %s, got %v", dueDate, got.DueDate)
+	}
+	if got.LastReviewedAt == nil || !got.LastReviewedAt.Equal(reviewedAt) {
+		t.Errorf("last reviewed at: expected %s, got %v", reviewedAt, got.LastReviewedAt)
+	}
+}
@@ -0,0 +1,98 @@
+package scheduler
+
+import (
+	"errors"
+	"math"
+	"time"
+	"vacabulary/models"
+)
+
+const (
+	defaultEaseFactor = 2.5
+	minEaseFactor     = 1.3
+
+	day = 24 * time.Hour
+)
+
+var (
+	errUnknownGrade = errors.New("unknown review grade")
+)
+
+// SM2Scheduler reschedules words with the SuperMemo-2 algorithm.
+type SM2Scheduler struct{}
+
+func NewSM2Scheduler() SM2Scheduler {
+	return SM2Scheduler{}
+}
+
+func (s *SM2Scheduler) Schedule(progress models.WordProgress, grade models.ReviewGrade, now time.Time) (models.WordProgress, error) {
+	quality, err := s.gradeToQuality(grade)
+	if err != nil {
+		return progress, err
+	}
+
+	easeFactor := progress.EaseFactor
+	if easeFactor == 0 {
+		easeFactor = defaultEaseFactor
+	}
+
+	// ease factor formula from the original SM-2 description
+	easeFactor = easeFactor + (0.1 - float64(5-quality)*(0.08+float64(5-quality)*0.02))
+	if easeFactor < minEaseFactor {
+		easeFactor = minEaseFactor
+	}
+
+	repetitions := progress.Repetitions
+	interval := progress.Interval
+
+	if quality < 3 {
+		// failed answer - start learning from the beginning
+		repetitions = 0
+		interval = 1
+	} else {
+		switch repetitions {
+		case 0:
+			interval = 1
+		case 1:
+			interval = 6
+		default:
+			interval = uint64(math.Round(float64(interval) * easeFactor))
+		}
+
+		// "easy" answer gets bonus to the interval
+		if grade == models.ReviewGradeEasy && repetitions > 0 {
+			interval = uint64(math.Round(float64(interval) * 1.3))
+		}
+
+		repetitions++
+	}
+
+	dueDate := now.Add(time.Duration(interval) * day)
+
+	scheduled := models.WordProgress{
+		EaseFactor:     easeFactor,
+		Interval:       interval,
+		Repetitions:    repetitions,
+		Box:            progress.Box,
+		DueDate:        &dueDate,
+		LastReviewedAt: &now,
+	}
+	scheduled.Mastery = MasteryLevel(scheduled)
+
+	return scheduled, nil
+}
+
+func (s *SM2Scheduler) gradeToQuality(grade models.ReviewGrade) (int, error) {
+	switch grade {
+	case models.ReviewGradeAgain:
+		return 1, nil
+	case models.ReviewGradeHard:
+		return 3, nil
+	case models.ReviewGradeGood:
+		return 4, nil
+	case models.ReviewGradeEasy:
+		return 5, nil
+	}
+
+	return 0, errUnknownGrade
+}
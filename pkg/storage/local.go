@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// LocalFilesPath is the route where files of local storage are served
+	LocalFilesPath = "/exports/files/"
+)
+
+var (
+	ErrInvalidKey       = errors.New("file key is not valid")
+	ErrInvalidSignature = errors.New("download url is not valid or expired")
+)
+
+// LocalStorage keeps files in the directory, urls are signed
+// with the secret and served by the api
+type LocalStorage struct {
+	dir     string
+	baseUrl string
+	secret  []byte
+}
+
+func NewLocalStorage(dir, baseUrl, secret string) (*LocalStorage, error) {
+	if dir == "" {
+		return nil, errors.New("directory of local storage is not configured")
+	}
+
+	if secret == "" {
+		return nil, errors.New("signing secret of local storage is not configured")
+	}
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalStorage{
+		dir:     dir,
+		baseUrl: strings.TrimRight(baseUrl, "/"),
+		secret:  []byte(secret),
+	}, nil
+}
+
+func (s *LocalStorage) Store(key string, data []byte, contentType string) error {
+	filePath, err := s.Path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(filePath), 0o755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, 0o644)
+}
+
+func (s *LocalStorage) URL(key string, ttl time.Duration) (string, error) {
+	if _, err := s.Path(key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(key, expires))
+
+	filesPath := url.URL{Path: LocalFilesPath + key}
+
+	return fmt.Sprintf("%s%s?%s", s.baseUrl, filesPath.EscapedPath(), query.Encode()), nil
+}
+
+func (s *LocalStorage) Delete(key string) error {
+	filePath, err := s.Path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Verify checks signature and expiration of the download url
+func (s *LocalStorage) Verify(key, expires, signature string) error {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(s.sign(key, expires)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// Path returns file path of the key, keys can't leave the storage directory
+func (s *LocalStorage) Path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if key == "" || cleaned != "/"+key {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStorage) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + ":" + expires))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"errors"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestStorage(t *testing.T) *LocalStorage {
+	s, err := NewLocalStorage(t.TempDir(), "http://localhost:8080/", "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return s
+}
+
+func TestLocalStoragePath(t *testing.T) {
+	s := newTestStorage(t)
+
+	tests := []struct {
+		name string
+		key  string
+		want string
+		err  error
+	}{
+		{name: "file", key: "export.csv", want: "export.csv"},
+		{name: "nested file", key: "exports/1/words.csv", want: filepath.Join("exports", "1", "words.csv")},
+		{name: "empty", key: "", err: ErrInvalidKey},
+		{name: "parent directory", key: "../secret.txt", err: ErrInvalidKey},
+		{name: "parent inside of key", key: "exports/../../secret.txt", err: ErrInvalidKey},
+		{name: "absolute", key: "/etc/passwd", err: ErrInvalidKey},
+		{name: "not clean", key: "exports//words.csv", err: ErrInvalidKey},
+		{name: "directory", key: "exports/", err: ErrInvalidKey},
+		{name: "current directory", key: "./words.csv", err: ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Path(tt.key)
+			if !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if tt.err != nil {
+				return
+			}
+			if want := filepath.Join(s.dir, tt.want); got != want {
+				t.Errorf("expected %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestLocalStorageVerify(t *testing.T) {
+	s := newTestStorage(t)
+
+	key := "exports/words.csv"
+	link, err := s.URL(key, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if parsed.Path != LocalFilesPath+key {
+		t.Fatalf("expected path %s, got %s", LocalFilesPath+key, parsed.Path)
+	}
+
+	expires := parsed.Query().Get("expires")
+	signature := parsed.Query().Get("signature")
+	expired := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		key       string
+		expires   string
+		signature string
+		err       error
+	}{
+		{name: "valid", key: key, expires: expires, signature: signature},
+		{name: "other key", key: "exports/other.csv", expires: expires, signature: signature, err: ErrInvalidSignature},
+		{name: "changed expiration", key: key, expires: expires + "0", signature: signature, err: ErrInvalidSignature},
+		{name: "expired", key: key, expires: expired, signature: s.sign(key, expired), err: ErrInvalidSignature},
+		{name: "expiration is not a number", key: key, expires: "never", signature: s.sign(key, "never"), err: ErrInvalidSignature},
+		{name: "wrong signature", key: key, expires: expires, signature: strings.Repeat("0", len(signature)), err: ErrInvalidSignature},
+		{name: "empty signature", key: key, expires: expires, err: ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Verify(tt.key, tt.expires, tt.signature)
+			if !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+				t.Errorf("expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestLocalStorageRejectsInvalidKeys(t *testing.T) {
+	s := newTestStorage(t)
+
+	if err := s.Store("../words.csv", []byte("dog"), "text/csv"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected invalid key on store, got %v", err)
+	}
+	if _, err := s.URL("../words.csv", time.Hour); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected invalid key on url, got %v", err)
+	}
+	if err := s.Delete("../words.csv"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected invalid key on delete, got %v", err)
+	}
+}
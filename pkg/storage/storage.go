@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"errors"
+	"time"
+	"vacabulary/config"
+	"vacabulary/pkg/s3"
+)
+
+var (
+	ErrUnknownType = errors.New("storage type must be one of s3, local")
+)
+
+// Storage keeps files by key and gives time-limited urls to download them
+type Storage interface {
+	Store(key string, data []byte, contentType string) error
+	URL(key string, ttl time.Duration) (string, error)
+	Delete(key string) error
+}
+
+// NewStorage returns storage selected by config, s3 is used by default
+func NewStorage(cfg config.StorageConfig, awsConfig config.AWSConfig) (Storage, error) {
+	switch cfg.Type {
+	case "", config.StorageTypeS3:
+		return s3.NewS3Manager(awsConfig, cfg), nil
+	case config.StorageTypeLocal:
+		return NewLocalStorage(cfg.Dir, cfg.BaseUrl, cfg.SigningSecret)
+	}
+
+	return nil, ErrUnknownType
+}
@@ -0,0 +1,51 @@
+package streak
+
+import "vacabulary/models"
+
+// Calculate counts days in a row when daily goal was reached.
+// Activity must contain every day in ascending order and end with today.
+func Calculate(activity []models.ActivityPerDay, dailyGoal uint64) models.Streak {
+	if dailyGoal == 0 {
+		dailyGoal = 1
+	}
+
+	streak := models.Streak{
+		DailyGoal: dailyGoal,
+	}
+
+	var running uint64
+	for _, day := range activity {
+		if day.Total >= dailyGoal {
+			running++
+		} else {
+			running = 0
+		}
+
+		if running > streak.Longest {
+			streak.Longest = running
+		}
+	}
+
+	if len(activity) == 0 {
+		return streak
+	}
+
+	today := activity[len(activity)-1]
+	streak.TodayProgress = today.Total
+	streak.GoalReachedToday = today.Total >= dailyGoal
+
+	// streak is not broken while user still has time to reach today's goal
+	days := activity
+	if !streak.GoalReachedToday {
+		days = activity[:len(activity)-1]
+	}
+
+	for i := len(days) - 1; i >= 0; i-- {
+		if days[i].Total < dailyGoal {
+			break
+		}
+		streak.Current++
+	}
+
+	return streak
+}
@@ -0,0 +1,74 @@
+package translator
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"vacabulary/config"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/translate"
+)
+
+// AwsTranslator translates words with AWS Translate, session is created once
+// and shared by concurrent requests
+type AwsTranslator struct {
+	client *translate.Translate
+}
+
+// NewAwsTranslator returns translator which waits for AWS no longer than timeout
+func NewAwsTranslator(config config.AWSConfig, timeout time.Duration) (*AwsTranslator, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      &config.Region,
+		Credentials: credentials.NewStaticCredentials(config.AccessId, config.Secret, ""),
+		HTTPClient:  &http.Client{Timeout: timeout},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AwsTranslator{
+		client: translate.New(sess),
+	}, nil
+}
+
+func (t *AwsTranslator) Name() string {
+	return config.TranslatorProviderAWS
+}
+
+func (t *AwsTranslator) TranslateWord(origin string, langFrom, langTo string) (string, error) {
+	sourceLangCode, err := isoLanguageCode(langFrom)
+	if err != nil {
+		return "", err
+	}
+
+	targetLangCode, err := isoLanguageCode(langTo)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := t.client.Text(&translate.TextInput{
+		SourceLanguageCode: aws.String(sourceLangCode),
+		TargetLanguageCode: aws.String(targetLangCode),
+		Text:               aws.String(origin),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == translate.ErrCodeUnsupportedLanguagePairException {
+			return "", fmt.Errorf("%w: %s", ErrUnsupportedLanguage, awsErr.Message())
+		}
+		return "", err
+	}
+
+	translation := strings.TrimSpace(aws.StringValue(response.TranslatedText))
+	if translation == "" {
+		return "", ErrNoTranslation
+	}
+
+	return translation, nil
+}
@@ -0,0 +1,64 @@
+package translator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ChainTranslator tries translators in order until one of them translates the word
+type ChainTranslator struct {
+	translators []Translator
+}
+
+func NewChainTranslator(translators ...Translator) *ChainTranslator {
+	return &ChainTranslator{
+		translators: translators,
+	}
+}
+
+func (c *ChainTranslator) Name() string {
+	names := []string{}
+	for _, t := range c.translators {
+		names = append(names, t.Name())
+	}
+
+	return strings.Join(names, ",")
+}
+
+// TranslateWord returns ErrUnsupportedLanguage when no translator supports languages,
+// ErrNoTranslation when translators which support them don't know the word
+// and ErrTranslationFailed when any of translators failed
+func (c *ChainTranslator) TranslateWord(origin string, langFrom, langTo string) (string, error) {
+	failures := []string{}
+	unsupported, notFound := 0, 0
+
+	for _, t := range c.translators {
+		translation, err := t.TranslateWord(origin, langFrom, langTo)
+		if err == nil {
+			return translation, nil
+		}
+
+		failures = append(failures, fmt.Sprintf("%s: %s", t.Name(), err.Error()))
+
+		switch {
+		case errors.Is(err, ErrUnsupportedLanguage):
+			unsupported++
+		case errors.Is(err, ErrNoTranslation):
+			notFound++
+		default:
+			fmt.Printf("translator %s failed: %s\n", t.Name(), err.Error())
+		}
+	}
+
+	details := strings.Join(failures, "; ")
+
+	switch len(c.translators) {
+	case unsupported:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedLanguage, details)
+	case unsupported + notFound:
+		return "", fmt.Errorf("%w: %s", ErrNoTranslation, details)
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrTranslationFailed, details)
+}
@@ -0,0 +1,78 @@
+package translator
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeTranslator struct {
+	name        string
+	translation string
+	err         error
+}
+
+func (f fakeTranslator) Name() string {
+	return f.name
+}
+
+func (f fakeTranslator) TranslateWord(origin string, langFrom, langTo string) (string, error) {
+	return f.translation, f.err
+}
+
+func TestChainTranslator(t *testing.T) {
+	unsupported := fakeTranslator{name: "unsupported", err: ErrUnsupportedLanguage}
+	notFound := fakeTranslator{name: "notFound", err: ErrNoTranslation}
+	failed := fakeTranslator{name: "failed", err: errors.New("timeout")}
+
+	tests := []struct {
+		name        string
+		translators []Translator
+		want        string
+		err         error
+	}{
+		{
+			name:        "first translation is returned",
+			translators: []Translator{notFound, fakeTranslator{name: "first", translation: "пес"}, fakeTranslator{name: "second", translation: "собака"}},
+			want:        "пес",
+		},
+		{
+			name:        "translation after failure",
+			translators: []Translator{failed, fakeTranslator{name: "ok", translation: "пес"}},
+			want:        "пес",
+		},
+		{
+			name:        "all unsupported",
+			translators: []Translator{unsupported, unsupported},
+			err:         ErrUnsupportedLanguage,
+		},
+		{
+			name:        "not found by supported",
+			translators: []Translator{unsupported, notFound},
+			err:         ErrNoTranslation,
+		},
+		{
+			name:        "any failure",
+			translators: []Translator{notFound, failed, unsupported},
+			err:         ErrTranslationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewChainTranslator(tt.translators...).TranslateWord("dog", "en", "ua")
+			if !errors.Is(err, tt.err) || (tt.err == nil && err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestChainTranslatorName(t *testing.T) {
+	chain := NewChainTranslator(fakeTranslator{name: "aws"}, fakeTranslator{name: "dictionary"})
+	if name := chain.Name(); name != "aws,dictionary" {
+		t.Errorf("expected aws,dictionary, got %s", name)
+	}
+}
@@ -0,0 +1,92 @@
+package translator
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"vacabulary/config"
+)
+
+// DictionaryTranslator translates words offline by dictionary file.
+// Every line of the file is tab separated: langFrom, langTo, word, translation.
+// Empty lines and lines starting with # are skipped, the first translation of the word is used.
+type DictionaryTranslator struct {
+	// translations by "langFrom:langTo" and lower case word
+	translations map[string]map[string]string
+}
+
+func NewDictionaryTranslator(path string) (*DictionaryTranslator, error) {
+	if path == "" {
+		return nil, fmt.Errorf("dictionary path is required")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	t := &DictionaryTranslator{
+		translations: map[string]map[string]string{},
+	}
+
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		value := strings.TrimSpace(scanner.Text())
+		if value == "" || strings.HasPrefix(value, "#") {
+			continue
+		}
+
+		fields := strings.Split(value, "\t")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("dictionary line %d must have 4 tab separated fields", line)
+		}
+
+		pair := languagePair(fields[0], fields[1])
+		word := strings.ToLower(strings.TrimSpace(fields[2]))
+		translation := strings.TrimSpace(fields[3])
+		if word == "" || translation == "" {
+			continue
+		}
+
+		if t.translations[pair] == nil {
+			t.translations[pair] = map[string]string{}
+		}
+
+		if _, ok := t.translations[pair][word]; !ok {
+			t.translations[pair][word] = translation
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (t *DictionaryTranslator) Name() string {
+	return config.TranslatorProviderDictionary
+}
+
+func (t *DictionaryTranslator) TranslateWord(origin string, langFrom, langTo string) (string, error) {
+	words, ok := t.translations[languagePair(langFrom, langTo)]
+	if !ok {
+		return "", ErrUnsupportedLanguage
+	}
+
+	translation, ok := words[strings.ToLower(strings.TrimSpace(origin))]
+	if !ok {
+		return "", ErrNoTranslation
+	}
+
+	return translation, nil
+}
+
+func languagePair(langFrom, langTo string) string {
+	return strings.ToLower(strings.TrimSpace(langFrom)) + ":" + strings.ToLower(strings.TrimSpace(langTo))
+}
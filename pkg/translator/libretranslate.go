@@ -0,0 +1,104 @@
+package translator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"vacabulary/config"
+)
+
+// LibreTranslator translates words with LibreTranslate compatible api
+type LibreTranslator struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func NewLibreTranslator(url, apiKey string, timeout time.Duration) (*LibreTranslator, error) {
+	if url == "" {
+		return nil, errors.New("libretranslate url is required")
+	}
+
+	return &LibreTranslator{
+		url:    strings.TrimSuffix(url, "/"),
+		apiKey: apiKey,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (t *LibreTranslator) Name() string {
+	return config.TranslatorProviderLibreTranslate
+}
+
+type libreTranslateRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	ApiKey string `json:"api_key,omitempty"`
+}
+
+type libreTranslateResponse struct {
+	TranslatedText string `json:"translatedText"`
+	Error          string `json:"error"`
+}
+
+func (t *LibreTranslator) TranslateWord(origin string, langFrom, langTo string) (string, error) {
+	sourceLangCode, err := isoLanguageCode(langFrom)
+	if err != nil {
+		return "", err
+	}
+
+	targetLangCode, err := isoLanguageCode(langTo)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(libreTranslateRequest{
+		Q:      origin,
+		Source: sourceLangCode,
+		Target: targetLangCode,
+		Format: "text",
+		ApiKey: t.apiKey,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	response, err := t.client.Post(t.url+"/translate", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+
+	result := libreTranslateResponse{}
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return "", fmt.Errorf("libretranslate response is not valid, status %d", response.StatusCode)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		// unsupported languages are reported with bad request
+		if response.StatusCode == http.StatusBadRequest && strings.Contains(result.Error, "not supported") {
+			return "", fmt.Errorf("%w: %s", ErrUnsupportedLanguage, result.Error)
+		}
+		return "", fmt.Errorf("libretranslate status %d: %s", response.StatusCode, result.Error)
+	}
+
+	translation := strings.TrimSpace(result.TranslatedText)
+	if translation == "" {
+		return "", ErrNoTranslation
+	}
+
+	return translation, nil
+}
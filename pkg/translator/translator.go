@@ -2,76 +2,69 @@ package translator
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"vacabulary/config"
+)
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/translate"
+var (
+	ErrUnsupportedLanguage = errors.New("language is not supported by translator")
+	ErrNoTranslation       = errors.New("translation not found")
+	ErrTranslationFailed   = errors.New("translation failed")
+	ErrUnknownProvider     = errors.New("translator provider must be one of aws, libretranslate, dictionary")
 )
 
-type TranslatorManager struct {
-	awsSession *session.Session
-	config     config.AWSConfig
+// Translator translates one word, languages are codes used by collections
+type Translator interface {
+	Name() string
+	TranslateWord(origin string, langFrom, langTo string) (string, error)
 }
 
-func (tm *TranslatorManager) TranslateWord(origin string, langFrom, langTo string) (string, error) {
-	sess := tm._getAwsSession()
-
-	transl := translate.New(sess)
-
-	sourceLangCode, err := tm.languageCodeToAwsTranslationCode(langFrom)
-	if err != nil {
-		return "", nil
-	}
-
-	targetLangCode, err := tm.languageCodeToAwsTranslationCode(langTo)
-	if err != nil {
-		return "", nil
-	}
-
-	response, err := transl.Text(&translate.TextInput{
-		SourceLanguageCode: aws.String(sourceLangCode),
-		TargetLanguageCode: aws.String(targetLangCode),
-		Text:               aws.String(origin),
-	})
-	if err != nil {
-		return "", nil
+// NewTranslator returns providers selected by config, they are tried in configured order
+func NewTranslator(cfg config.TranslatorConfig, awsConfig config.AWSConfig) (Translator, error) {
+	translators := []Translator{}
+	for _, provider := range cfg.GetProviders() {
+		switch strings.ToLower(strings.TrimSpace(provider)) {
+		case config.TranslatorProviderAWS:
+			awsTranslator, err := NewAwsTranslator(awsConfig, cfg.Timeout())
+			if err != nil {
+				return nil, err
+			}
+			translators = append(translators, awsTranslator)
+		case config.TranslatorProviderLibreTranslate:
+			libreTranslator, err := NewLibreTranslator(cfg.LibreTranslate.Url, cfg.LibreTranslate.ApiKey, cfg.Timeout())
+			if err != nil {
+				return nil, err
+			}
+			translators = append(translators, libreTranslator)
+		case config.TranslatorProviderDictionary:
+			dictionary, err := NewDictionaryTranslator(cfg.DictionaryPath)
+			if err != nil {
+				return nil, err
+			}
+			translators = append(translators, dictionary)
+		default:
+			return nil, fmt.Errorf("%w, got %s", ErrUnknownProvider, provider)
+		}
 	}
 
-	return *response.TranslatedText, nil
-}
-
-func (tm *TranslatorManager) _getAwsSession() *session.Session {
-	if tm.awsSession == nil {
-		tm._setAwsSession()
+	if len(translators) == 1 {
+		return translators[0], nil
 	}
 
-	return tm.awsSession
+	return NewChainTranslator(translators...), nil
 }
 
-func (tm *TranslatorManager) _setAwsSession() {
-	sess := session.Must(session.NewSession(&aws.Config{
-		Region:      &tm.config.Region,
-		Credentials: credentials.NewStaticCredentials(tm.config.AccessId, tm.config.Secret, ""),
-	}))
-
-	tm.awsSession = sess
-}
-
-func NewTranslatorManager(config config.AWSConfig) TranslatorManager {
-	return TranslatorManager{
-		config: config,
-	}
-}
+// isoLanguageCode returns ISO 639-1 code of collection language, collections use ua for ukrainian
+func isoLanguageCode(langCode string) (string, error) {
+	langCode = strings.ToLower(strings.TrimSpace(langCode))
 
-func (tm *TranslatorManager) languageCodeToAwsTranslationCode(langCode string) (string, error) {
 	switch langCode {
+	case "":
+		return "", ErrUnsupportedLanguage
 	case "ua":
 		return "uk", nil
-	case "en":
-		return "en", nil
 	}
 
-	return "", errors.New("unknown language code")
+	return langCode, nil
 }
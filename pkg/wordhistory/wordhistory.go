@@ -0,0 +1,79 @@
+package wordhistory
+
+import (
+	"reflect"
+	"vacabulary/models"
+)
+
+// Snapshot returns editable fields of the word, main translation and
+// sentence are the first items of Translations and Sentences
+func Snapshot(word models.Word) models.WordSnapshot {
+	return models.WordSnapshot{
+		Word:          word.Word,
+		Translations:  variants(word.Translation, word.Translations),
+		PartOfSpeech:  word.PartOfSpeech,
+		Sentences:     variants(word.Scentance, word.Sentences),
+		Synonyms:      models.NonEmpty(word.Synonyms),
+		Antonyms:      models.NonEmpty(word.Antonyms),
+		Note:          word.Note,
+		Transcription: word.Transcription,
+		Tags:          models.NormalizeTags(word.Tags),
+	}
+}
+
+// Apply returns the word with editable fields taken from snapshot
+func Apply(word models.Word, snapshot models.WordSnapshot) models.Word {
+	word.Word = snapshot.Word
+	word.Translation = first(snapshot.Translations)
+	word.Translations = snapshot.Translations
+	word.PartOfSpeech = snapshot.PartOfSpeech
+	word.Scentance = first(snapshot.Sentences)
+	word.Sentences = snapshot.Sentences
+	word.Synonyms = snapshot.Synonyms
+	word.Antonyms = snapshot.Antonyms
+	word.Note = snapshot.Note
+	word.Transcription = snapshot.Transcription
+	word.Tags = snapshot.Tags
+
+	return word
+}
+
+// Diff returns changed fields between two versions of the word
+func Diff(before, after models.Word) []models.WordChange {
+	old := Snapshot(before)
+	new := Snapshot(after)
+
+	changes := []models.WordChange{}
+	add := func(field string, oldValue, newValue interface{}) {
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, models.WordChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+
+	add("word", old.Word, new.Word)
+	add("translations", old.Translations, new.Translations)
+	add("partOfSpeech", old.PartOfSpeech, new.PartOfSpeech)
+	add("sentences", old.Sentences, new.Sentences)
+	add("synonyms", old.Synonyms, new.Synonyms)
+	add("antonyms", old.Antonyms, new.Antonyms)
+	add("note", old.Note, new.Note)
+	add("transcription", old.Transcription, new.Transcription)
+	add("tags", old.Tags, new.Tags)
+
+	return changes
+}
+
+// variants puts main value first without empty values and duplicates,
+// the same way words are stored in elasticsearch
+func variants(main string, values []string) []string {
+	_, merged := models.MergeVariants(main, values)
+	return merged
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -0,0 +1,250 @@
+package wordimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"vacabulary/models"
+)
+
+const (
+	FormatCSV = "csv"
+	FormatTSV = "tsv"
+
+	// MaxRows limits rows of one import file
+	MaxRows = 5000
+)
+
+var (
+	ErrUnknownFormat = errors.New("format must be one of csv, tsv")
+	ErrTooManyRows   = fmt.Errorf("file can't have more than %d rows", MaxRows)
+)
+
+// Mapping keeps columns of the file for word fields.
+// Column is 1-based number or name from header, empty column is not imported.
+type Mapping struct {
+	Word         string `json:"word"`
+	Translation  string `json:"translation"`
+	PartOfSpeech string `json:"partOfSpeech"`
+	Sentence     string `json:"sentence"`
+}
+
+// Row is parsed line of the file, Line is counted from 1 with header
+type Row struct {
+	Line   int         `json:"line"`
+	Word   models.Word `json:"word"`
+	Errors []string    `json:"errors"`
+}
+
+func (r Row) IsValid() bool {
+	return len(r.Errors) == 0
+}
+
+// Delimiter returns separator of the values for format
+func Delimiter(format string) (rune, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return ',', nil
+	case FormatTSV:
+		return '\t', nil
+	}
+
+	return 0, ErrUnknownFormat
+}
+
+// Parse reads the file and maps columns to words, rows with empty word
+// or translation are returned with errors
+func Parse(r io.Reader, format string, hasHeader bool, mapping Mapping) ([]Row, error) {
+	format = strings.ToLower(format)
+	delimiter, err := Delimiter(format)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	// quotes are not used in tsv exports, so they are kept as text
+	reader.LazyQuotes = format == FormatTSV
+
+	var header []string
+	if hasHeader {
+		header, err = reader.Read()
+		if err == io.EOF {
+			return []Row{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		// utf-8 BOM is added by excel
+		if len(header) > 0 {
+			header[0] = strings.TrimPrefix(header[0], "\ufeff")
+		}
+	}
+
+	columns, err := mapping.columns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []Row{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rows = append(rows, Row{Line: parseErr.StartLine, Errors: []string{parseErr.Err.Error()}})
+				continue
+			}
+			return nil, err
+		}
+
+		if isEmpty(record) {
+			continue
+		}
+
+		// quoted values can take several lines
+		line, _ := reader.FieldPos(0)
+
+		if len(rows) >= MaxRows {
+			return nil, ErrTooManyRows
+		}
+
+		rows = append(rows, parseRow(line, record, columns))
+	}
+
+	return rows, nil
+}
+
+type columns struct {
+	word         int
+	translation  int
+	partOfSpeech int
+	sentence     int
+}
+
+// columns returns 0-based indexes of mapped columns, -1 for not mapped
+func (m Mapping) columns(header []string) (columns, error) {
+	var err error
+	c := columns{}
+
+	if strings.TrimSpace(m.Word) == "" || strings.TrimSpace(m.Translation) == "" {
+		return c, errors.New("word and translation columns are required")
+	}
+
+	if c.word, err = columnIndex("word", m.Word, header); err != nil {
+		return c, err
+	}
+	if c.translation, err = columnIndex("translation", m.Translation, header); err != nil {
+		return c, err
+	}
+	if c.partOfSpeech, err = columnIndex("partOfSpeech", m.PartOfSpeech, header); err != nil {
+		return c, err
+	}
+	if c.sentence, err = columnIndex("sentence", m.Sentence, header); err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+func columnIndex(field, column string, header []string) (int, error) {
+	column = strings.TrimSpace(column)
+	if column == "" {
+		return -1, nil
+	}
+
+	if number, err := strconv.Atoi(column); err == nil {
+		if number < 1 {
+			return 0, fmt.Errorf("%s column must be greater than 0", field)
+		}
+		return number - 1, nil
+	}
+
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%s column %q is not found in header", field, column)
+}
+
+func parseRow(line int, record []string, c columns) Row {
+	value := func(index int) string {
+		if index < 0 || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	return NewRow(line, models.Word{
+		Word:         value(c.word),
+		Translation:  value(c.translation),
+		PartOfSpeech: value(c.partOfSpeech),
+		Scentance:    value(c.sentence),
+	})
+}
+
+// NewRow validates word imported from any source
+func NewRow(line int, word models.Word) Row {
+	row := Row{
+		Line:   line,
+		Word:   word,
+		Errors: []string{},
+	}
+
+	if row.Word.Word == "" {
+		row.Errors = append(row.Errors, "word is empty")
+	}
+
+	if row.Word.Translation == "" {
+		row.Errors = append(row.Errors, "translation is empty")
+	}
+
+	return row
+}
+
+func isEmpty(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Export writes words with header which can be parsed back by column names
+func Export(words []models.Word, format string) ([]byte, error) {
+	format = strings.ToLower(format)
+	delimiter, err := Delimiter(format)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	writer := csv.NewWriter(buf)
+	writer.Comma = delimiter
+
+	records := [][]string{{"word", "translation", "partOfSpeech", "sentence", "transcription", "tags"}}
+	for _, w := range words {
+		records = append(records, []string{w.Word, w.Translation, w.PartOfSpeech, w.Scentance, w.Transcription, strings.Join(w.Tags, ",")})
+	}
+
+	err = writer.WriteAll(records)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
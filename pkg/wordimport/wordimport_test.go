@@ -0,0 +1,188 @@
+package wordimport
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"vacabulary/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		format    string
+		hasHeader bool
+		mapping   Mapping
+		want      []Row
+	}{
+		{
+			name:      "csv with header names",
+			content:   "\ufeffWord,Translation,Part\ndog,пес,noun\n",
+			format:    FormatCSV,
+			hasHeader: true,
+			mapping:   Mapping{Word: "word", Translation: "translation", PartOfSpeech: "part"},
+			want: []Row{
+				{Line: 2, Word: models.Word{Word: "dog", Translation: "пес", PartOfSpeech: "noun"}, Errors: []string{}},
+			},
+		},
+		{
+			name:    "tsv with column numbers keeps inner quotes",
+			content: "пес\tbig \"dog\"\tThe dog barks.\n",
+			format:  "TSV",
+			mapping: Mapping{Word: "2", Translation: "1", Sentence: "3"},
+			want: []Row{
+				{Line: 1, Word: models.Word{Word: "big \"dog\"", Translation: "пес", Scentance: "The dog barks."}, Errors: []string{}},
+			},
+		},
+		{
+			name:    "quoted value takes several lines",
+			content: "cat,\"кіт,\nкицька\"\n\n dog , пес \n",
+			format:  FormatCSV,
+			mapping: Mapping{Word: "1", Translation: "2"},
+			want: []Row{
+				{Line: 1, Word: models.Word{Word: "cat", Translation: "кіт,\nкицька"}, Errors: []string{}},
+				{Line: 4, Word: models.Word{Word: "dog", Translation: "пес"}, Errors: []string{}},
+			},
+		},
+		{
+			name:    "empty values and missing columns",
+			content: "dog,\n,пес\ncat\n",
+			format:  FormatCSV,
+			mapping: Mapping{Word: "1", Translation: "2"},
+			want: []Row{
+				{Line: 1, Word: models.Word{Word: "dog"}, Errors: []string{"translation is empty"}},
+				{Line: 2, Word: models.Word{Translation: "пес"}, Errors: []string{"word is empty"}},
+				{Line: 3, Word: models.Word{Word: "cat"}, Errors: []string{"translation is empty"}},
+			},
+		},
+		{
+			name:    "broken quote is returned as row error",
+			content: "dog,\"пес\"x\n",
+			format:  FormatCSV,
+			mapping: Mapping{Word: "1", Translation: "2"},
+			want: []Row{
+				{Line: 1, Errors: []string{`extraneous or missing " in quoted-field`}},
+			},
+		},
+		{
+			name:      "only header",
+			content:   "word,translation\n",
+			format:    FormatCSV,
+			hasHeader: true,
+			mapping:   Mapping{Word: "word", Translation: "translation"},
+			want:      []Row{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Parse(strings.NewReader(tt.content), tt.format, tt.hasHeader, tt.mapping)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, rows)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		format    string
+		hasHeader bool
+		mapping   Mapping
+		err       string
+	}{
+		{
+			name:    "unknown format",
+			content: "dog,пес\n",
+			format:  "xlsx",
+			mapping: Mapping{Word: "1", Translation: "2"},
+			err:     ErrUnknownFormat.Error(),
+		},
+		{
+			name:    "translation is not mapped",
+			content: "dog,пес\n",
+			format:  FormatCSV,
+			mapping: Mapping{Word: "1"},
+			err:     "word and translation columns are required",
+		},
+		{
+			name:    "column number below one",
+			content: "dog,пес\n",
+			format:  FormatCSV,
+			mapping: Mapping{Word: "0", Translation: "2"},
+			err:     "word column must be greater than 0",
+		},
+		{
+			name:      "column is not in header",
+			content:   "word,translation\ndog,пес\n",
+			format:    FormatCSV,
+			hasHeader: true,
+			mapping:   Mapping{Word: "word", Translation: "meaning"},
+			err:       `translation column "meaning" is not found in header`,
+		},
+		{
+			name:    "too many rows",
+			content: strings.Repeat("dog,пес\n", MaxRows+1),
+			format:  FormatCSV,
+			mapping: Mapping{Word: "1", Translation: "2"},
+			err:     ErrTooManyRows.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.content), tt.format, tt.hasHeader, tt.mapping)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestExportIsParsedBack(t *testing.T) {
+	words := []models.Word{
+		{Word: "dog", Translation: "пес, собака", PartOfSpeech: "noun", Scentance: "The \"dog\" barks.", Tags: []string{"animals", "pets"}},
+	}
+
+	for _, format := range []string{FormatCSV, FormatTSV} {
+		t.Run(format, func(t *testing.T) {
+			data, err := Export(words, format)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			rows, err := Parse(strings.NewReader(string(data)), format, true, Mapping{
+				Word:         "word",
+				Translation:  "translation",
+				PartOfSpeech: "partOfSpeech",
+				Sentence:     "sentence",
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(rows) != 1 || !rows[0].IsValid() {
+				t.Fatalf("expected one valid row, got %+v", rows)
+			}
+
+			got := rows[0].Word
+			if got.Word != words[0].Word || got.Translation != words[0].Translation || got.PartOfSpeech != words[0].PartOfSpeech {
+				t.Errorf("expected %+v, got %+v", words[0], got)
+			}
+		})
+	}
+}
+
+func TestDelimiter(t *testing.T) {
+	if _, err := Delimiter("json"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("expected unknown format error, got %v", err)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"strings"
 	"time"
 	myElastic "vacabulary/db/elastic"
 	"vacabulary/models"
@@ -13,6 +15,25 @@ import (
 	"github.com/olivere/elastic/v7"
 )
 
+const (
+	// bulkChunkSize limits words in one bulk request
+	bulkChunkSize = 1000
+
+	// getByWordsChunkSize limits words looked up by one query
+	getByWordsChunkSize = 200
+
+	// scrollKeepAlive is time search context is kept between pages of words
+	scrollKeepAlive = "1m"
+
+	// replaceTagsAttempts limits runs of tags update when words are changed concurrently
+	replaceTagsAttempts = 3
+)
+
+var (
+	ErrTagsConflict = errors.New("words were changed during tags update, try again")
+	ErrInvalidPage  = errors.New("size and page must be positive")
+)
+
 type collectionWordsRepo struct {
 	client *elastic.Client
 }
@@ -20,17 +41,37 @@ type collectionWordsRepo struct {
 type Words interface {
 	Create(word models.Word, wordsCtx CollectionWordsOperationCtx) error
 	BulkCreate(word []models.Word, wordsCtx CollectionWordsOperationCtx) error
+	BulkImport(words []models.Word, wordsCtx CollectionWordsOperationCtx) error
 	Update(word models.Word, wordsCtx CollectionWordsOperationCtx) error
 	DeleteById(id string, wordsCtx CollectionWordsOperationCtx) error
 	Get(origin string, wordsCtx CollectionWordsOperationCtx) (*models.Word, error)
 	GetById(origin string, wordsCtx CollectionWordsOperationCtx) (*models.Word, error)
 	GetByTranslation(translation string, wordsCtx CollectionWordsOperationCtx) (*models.Word, error)
 	GetAll(size, page uint64, wordsCtx CollectionWordsOperationCtx) ([]models.Word, uint64, error)
+	GetAllFiltered(size, page uint64, filter models.WordsFilter, wordsCtx CollectionWordsOperationCtx) ([]models.Word, uint64, error)
+	GetAllWords(wordsCtx CollectionWordsOperationCtx) ([]models.Word, error)
 	GetByWords(words []string, wordsCtx CollectionWordsOperationCtx) ([]models.Word, error)
+	UpdateCollectionId(id string, collectionId uint64, wordsCtx CollectionWordsOperationCtx) error
 	Search(settings models.SearchSettings, wordsCtx CollectionWordsOperationCtx) ([]models.Word, error)
 	SearchOnCollections(settings models.SearchSettings, userIds []uint64) ([]models.Word, error)
 	GetAllWordsCount(userIds []uint64) (int64, error)
-	GetCountOfWordsPerTime(userIds []uint64, time string) ([]models.WordsAddedPerTime, error)
+	GetCountOfWordsPerTime(userIds []uint64, time string, timezone string) ([]models.WordsAddedPerTime, error)
+	GetDue(dueTo time.Time, size uint64, wordsCtx CollectionWordsOperationCtx) ([]models.Word, uint64, error)
+	UpdateProgress(id string, progress models.WordProgress, wordsCtx CollectionWordsOperationCtx) error
+	GetCountOfWordsPerCollection(userId uint64) ([]models.WordsPerCollection, error)
+	GetCountOfWordsInCollections(collections []CollectionWordsOperationCtx) ([]models.WordsPerCollection, error)
+	GetCountOfWordsPerPartOfSpeech(userId uint64) ([]models.WordsPerPartOfSpeech, error)
+	GetCountOfWordsPerDay(userId uint64, from, to time.Time, timezone string) ([]models.WordsAddedPerTime, error)
+	GetTags(userId uint64) ([]models.TagCount, error)
+	ReplaceTags(userId uint64, tags []string, newTag string) (uint64, error)
+	DeleteByCollectionId(wordsCtx CollectionWordsOperationCtx) (uint64, error)
+	GetIdsByCollectionId(wordsCtx CollectionWordsOperationCtx) ([]string, error)
+	SoftDeleteById(id string, deletedAt time.Time, wordsCtx CollectionWordsOperationCtx) error
+	RestoreById(id string, wordsCtx CollectionWordsOperationCtx) error
+	SoftDeleteByCollectionId(deletedAt time.Time, wordsCtx CollectionWordsOperationCtx) error
+	RestoreByCollectionId(wordsCtx CollectionWordsOperationCtx) error
+	GetDeleted(userId uint64) ([]models.Word, error)
+	PurgeDeleted(userId uint64, deletedBefore time.Time) ([]string, error)
 }
 
 func NewCollectionWordsRepo(client *elastic.Client) Words {
@@ -40,12 +81,119 @@ func NewCollectionWordsRepo(client *elastic.Client) Words {
 }
 
 type ElasticWord struct {
-	CollectionId uint64    `json:"collection_id"`
-	Word         string    `json:"word"`
-	Translation  string    `json:"translation"`
-	PartOfSpeech string    `json:"part_of_speech"`
-	Scentance    string    `json:"scentance"`
-	CreatedAt    time.Time `json:"created_at"`
+	CollectionId  uint64     `json:"collection_id"`
+	Word          string     `json:"word"`
+	Translation   string     `json:"translation"`
+	Translations  []string   `json:"translations"`
+	PartOfSpeech  string     `json:"part_of_speech"`
+	Scentance     string     `json:"scentance"`
+	Sentences     []string   `json:"sentences"`
+	Synonyms      []string   `json:"synonyms"`
+	Antonyms      []string   `json:"antonyms"`
+	Note          string     `json:"note"`
+	Transcription string     `json:"transcription"`
+	Tags          []string   `json:"tags"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	// CollectionDeletedAt is set when collection of the word is moved to trash
+	CollectionDeletedAt *time.Time           `json:"collection_deleted_at,omitempty"`
+	Progress            *ElasticWordProgress `json:"progress,omitempty"`
+}
+
+type ElasticWordProgress struct {
+	EaseFactor     float64             `json:"ease_factor"`
+	Interval       uint64              `json:"interval"`
+	Repetitions    uint64              `json:"repetitions"`
+	Box            uint64              `json:"box"`
+	Mastery        models.MasteryLevel `json:"mastery"`
+	DueDate        *time.Time          `json:"due_date"`
+	LastReviewedAt *time.Time          `json:"last_reviewed_at"`
+}
+
+func (w *ElasticWord) FromModel(id string) models.Word {
+	// documents created before multiple translations were
+	// introduced have only single translation and sentence
+	translation, translations := models.MergeVariants(w.Translation, w.Translations)
+	scentance, sentences := models.MergeVariants(w.Scentance, w.Sentences)
+
+	word := models.Word{
+		Id:            id,
+		CollectionId:  w.CollectionId,
+		Word:          w.Word,
+		Translation:   translation,
+		Translations:  translations,
+		PartOfSpeech:  w.PartOfSpeech,
+		Scentance:     scentance,
+		Sentences:     sentences,
+		Synonyms:      models.NonEmpty(w.Synonyms),
+		Antonyms:      models.NonEmpty(w.Antonyms),
+		Note:          w.Note,
+		Transcription: w.Transcription,
+		Tags:          models.NonEmpty(w.Tags),
+		CreatedAt:     w.CreatedAt,
+		DeletedAt:     w.DeletedAt,
+	}
+
+	if w.Progress != nil {
+		word.Progress = w.Progress.FromModel()
+	} else {
+		word.Progress.Mastery = models.MasteryLevelNew
+	}
+
+	return word
+}
+
+func (p *ElasticWordProgress) FromModel() models.WordProgress {
+	return models.WordProgress{
+		EaseFactor:     p.EaseFactor,
+		Interval:       p.Interval,
+		Repetitions:    p.Repetitions,
+		Box:            p.Box,
+		Mastery:        p.Mastery,
+		DueDate:        p.DueDate,
+		LastReviewedAt: p.LastReviewedAt,
+	}
+}
+
+func ToElasticWord(word models.Word) ElasticWord {
+	translation, translations := models.MergeVariants(word.Translation, word.Translations)
+	scentance, sentences := models.MergeVariants(word.Scentance, word.Sentences)
+
+	elasticWord := ElasticWord{
+		CollectionId:  word.CollectionId,
+		Word:          word.Word,
+		Translation:   translation,
+		Translations:  translations,
+		PartOfSpeech:  word.PartOfSpeech,
+		Scentance:     scentance,
+		Sentences:     sentences,
+		Synonyms:      models.NonEmpty(word.Synonyms),
+		Antonyms:      models.NonEmpty(word.Antonyms),
+		Note:          word.Note,
+		Transcription: word.Transcription,
+		Tags:          models.NormalizeTags(word.Tags),
+		CreatedAt:     word.CreatedAt,
+	}
+
+	// never reviewed words are stored without progress,
+	// so partial updates don't reset the review state
+	if word.Progress.DueDate != nil {
+		elasticWord.Progress = ToElasticWordProgress(word.Progress)
+	}
+
+	return elasticWord
+}
+
+func ToElasticWordProgress(progress models.WordProgress) *ElasticWordProgress {
+	return &ElasticWordProgress{
+		EaseFactor:     progress.EaseFactor,
+		Interval:       progress.Interval,
+		Repetitions:    progress.Repetitions,
+		Box:            progress.Box,
+		Mastery:        progress.Mastery,
+		DueDate:        progress.DueDate,
+		LastReviewedAt: progress.LastReviewedAt,
+	}
 }
 
 type CollectionWordsOperationCtx struct {
@@ -59,14 +207,8 @@ func (r *collectionWordsRepo) Create(word models.Word, wordsCtx CollectionWordsO
 		return err
 	}
 
-	elasticWord := ElasticWord{
-		CollectionId: word.CollectionId,
-		Word:         word.Word,
-		Translation:  word.Translation,
-		PartOfSpeech: word.PartOfSpeech,
-		Scentance:    word.Scentance,
-		CreatedAt:    time.Now(),
-	}
+	elasticWord := ToElasticWord(word)
+	elasticWord.CreatedAt = time.Now()
 
 	ctx := context.Background()
 	_, err = r.client.Index().Index(index.GetName()).BodyJson(elasticWord).Refresh("true").Do(ctx)
@@ -78,39 +220,71 @@ func (r *collectionWordsRepo) Create(word models.Word, wordsCtx CollectionWordsO
 }
 
 func (r *collectionWordsRepo) BulkCreate(words []models.Word, wordsCtx CollectionWordsOperationCtx) error {
-	index, err := r.getIndex(wordsCtx)
-	if err != nil {
-		return err
-	}
-
 	elasticWords := []ElasticWord{}
 	for _, word := range words {
-		elasticWords = append(elasticWords, ElasticWord{
-			CollectionId: word.CollectionId,
-			Word:         word.Word,
-			Translation:  word.Translation,
-			PartOfSpeech: word.PartOfSpeech,
-			Scentance:    word.Scentance,
-			CreatedAt:    time.Now(),
-		})
+		elasticWord := ToElasticWord(word)
+		elasticWord.CreatedAt = time.Now()
+
+		elasticWords = append(elasticWords, elasticWord)
 	}
 
-	bulk := r.client.Bulk()
-	for _, eWord := range elasticWords {
-		req := elastic.NewBulkIndexRequest()
+	return r.bulkIndex(elasticWords, wordsCtx)
+}
 
-		req.Index(index.GetName())
-		req.Doc(eWord)
+// BulkImport creates words restored from archive, creation date and progress are kept
+func (r *collectionWordsRepo) BulkImport(words []models.Word, wordsCtx CollectionWordsOperationCtx) error {
+	elasticWords := []ElasticWord{}
+	for _, word := range words {
+		elasticWord := ToElasticWord(word)
+		if elasticWord.CreatedAt.IsZero() {
+			elasticWord.CreatedAt = time.Now()
+		}
 
-		bulk.Add(req)
+		elasticWords = append(elasticWords, elasticWord)
 	}
 
-	ctx := context.Background()
-	_, err = bulk.Refresh("true").Do(ctx)
+	return r.bulkIndex(elasticWords, wordsCtx)
+}
+
+// bulkIndex sends words by chunks, so big imports don't exceed request size
+func (r *collectionWordsRepo) bulkIndex(elasticWords []ElasticWord, wordsCtx CollectionWordsOperationCtx) error {
+	index, err := r.getIndex(wordsCtx)
 	if err != nil {
 		return err
 	}
 
+	ctx := context.Background()
+
+	for from := 0; from < len(elasticWords); from += bulkChunkSize {
+		to := from + bulkChunkSize
+		if to > len(elasticWords) {
+			to = len(elasticWords)
+		}
+
+		bulk := r.client.Bulk()
+		for _, eWord := range elasticWords[from:to] {
+			req := elastic.NewBulkIndexRequest()
+
+			req.Index(index.GetName())
+			req.Doc(eWord)
+
+			bulk.Add(req)
+		}
+
+		res, err := bulk.Refresh("true").Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		if res.Errors {
+			failed := res.Failed()
+			if len(failed) > 0 && failed[0].Error != nil {
+				return fmt.Errorf("failed to index %d words: %s", len(failed), failed[0].Error.Reason)
+			}
+			return errors.New("failed to index words")
+		}
+	}
+
 	return nil
 }
 
@@ -142,6 +316,7 @@ func (r *collectionWordsRepo) Get(word string, wordsCtx CollectionWordsOperation
 	// q2 := elastic.NewMatchQuery("collection_id", collectionId)
 
 	query.Must(q1)
+	excludeDeleted(query)
 
 	searchResult, err := r.client.Search().Index(index.GetName()).Query(query).Do(ctx)
 	if err != nil {
@@ -155,14 +330,8 @@ func (r *collectionWordsRepo) Get(word string, wordsCtx CollectionWordsOperation
 			continue
 		}
 
-		findedWord = &models.Word{
-			CollectionId: word.CollectionId,
-			Word:         word.Word,
-			Translation:  word.Translation,
-			PartOfSpeech: word.PartOfSpeech,
-			Scentance:    word.Scentance,
-			CreatedAt:    word.CreatedAt,
-		}
+		w := word.FromModel("")
+		findedWord = &w
 	}
 
 	return findedWord, nil
@@ -178,6 +347,9 @@ func (r *collectionWordsRepo) GetById(id string, wordsCtx CollectionWordsOperati
 
 	searchResult, err := r.client.Get().Index(index.GetName()).Id(id).Do(ctx)
 	if err != nil {
+		if elastic.IsNotFound(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
@@ -187,15 +359,7 @@ func (r *collectionWordsRepo) GetById(id string, wordsCtx CollectionWordsOperati
 		return nil, err
 	}
 
-	findedWord := models.Word{
-		Id:           searchResult.Id,
-		CollectionId: word.CollectionId,
-		Word:         word.Word,
-		Translation:  word.Translation,
-		PartOfSpeech: word.PartOfSpeech,
-		Scentance:    word.Scentance,
-		CreatedAt:    word.CreatedAt,
-	}
+	findedWord := word.FromModel(searchResult.Id)
 
 	return &findedWord, nil
 }
@@ -208,7 +372,7 @@ func (r *collectionWordsRepo) GetByTranslation(translation string, wordsCtx Coll
 
 	ctx := context.Background()
 
-	query := elastic.NewTermQuery("translation", translation)
+	query := excludeDeleted(elastic.NewBoolQuery().Must(elastic.NewTermQuery("translation", translation)))
 
 	searchResult, err := r.client.Search().Index(index.GetName()).Query(query).Do(ctx)
 	if err != nil {
@@ -221,14 +385,7 @@ func (r *collectionWordsRepo) GetByTranslation(translation string, wordsCtx Coll
 			continue
 		}
 
-		words = append(words, models.Word{
-			CollectionId: word.CollectionId,
-			Word:         word.Word,
-			Translation:  word.Translation,
-			PartOfSpeech: word.PartOfSpeech,
-			Scentance:    word.Scentance,
-			CreatedAt:    word.CreatedAt,
-		})
+		words = append(words, word.FromModel(""))
 	}
 
 	if len(words) == 0 {
@@ -239,6 +396,15 @@ func (r *collectionWordsRepo) GetByTranslation(translation string, wordsCtx Coll
 }
 
 func (r *collectionWordsRepo) GetAll(size, page uint64, wordsCtx CollectionWordsOperationCtx) ([]models.Word, uint64, error) {
+	return r.GetAllFiltered(size, page, models.WordsFilter{}, wordsCtx)
+}
+
+// GetAllFiltered returns page of words starting from 1, GetAllWords returns all words
+func (r *collectionWordsRepo) GetAllFiltered(size, page uint64, filter models.WordsFilter, wordsCtx CollectionWordsOperationCtx) ([]models.Word, uint64, error) {
+	if size == 0 || page == 0 {
+		return nil, 0, ErrInvalidPage
+	}
+
 	index, err := r.getIndex(wordsCtx)
 	if err != nil {
 		return nil, 0, err
@@ -248,15 +414,13 @@ func (r *collectionWordsRepo) GetAll(size, page uint64, wordsCtx CollectionWords
 
 	// query := elastic.NewMatchQuery("collection_id", collectionId)
 
-	var searchResult *elastic.SearchResult
+	query := elastic.NewBoolQuery()
+	applyWordsFilter(query, filter)
 
-	if size == 0 && page == 0 {
-		// get all words
-		searchResult, err = r.client.Search().Index(index.GetName()).Sort("created_at", false).Size(1000).Do(ctx)
-	} else {
-		from := size * (page - 1)
-		searchResult, err = r.client.Search().Index(index.GetName()).Sort("created_at", false).Size(int(size)).From(int(from)).Do(ctx)
-	}
+	search := r.client.Search().Index(index.GetName()).Query(query).SortBy(wordsSorters(filter)...)
+
+	from := size * (page - 1)
+	searchResult, err := search.Size(int(size)).From(int(from)).Do(ctx)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -271,15 +435,7 @@ func (r *collectionWordsRepo) GetAll(size, page uint64, wordsCtx CollectionWords
 			continue
 		}
 
-		words = append(words, models.Word{
-			Id:           hit.Id,
-			CollectionId: word.CollectionId,
-			Word:         word.Word,
-			Translation:  word.Translation,
-			PartOfSpeech: word.PartOfSpeech,
-			Scentance:    word.Scentance,
-			CreatedAt:    word.CreatedAt,
-		})
+		words = append(words, word.FromModel(hit.Id))
 	}
 
 	return words, totalHits, nil
@@ -291,14 +447,7 @@ func (r *collectionWordsRepo) Update(word models.Word, wordsCtx CollectionWordsO
 		return err
 	}
 
-	elasticWord := ElasticWord{
-		CollectionId: word.CollectionId,
-		Word:         word.Word,
-		Translation:  word.Translation,
-		PartOfSpeech: word.PartOfSpeech,
-		Scentance:    word.Scentance,
-		CreatedAt:    word.CreatedAt,
-	}
+	elasticWord := ToElasticWord(word)
 
 	ctx := context.Background()
 	_, err = r.client.Update().Index(index.GetName()).Refresh("true").Doc(elasticWord).Id(word.Id).Do(ctx)
@@ -309,49 +458,111 @@ func (r *collectionWordsRepo) Update(word models.Word, wordsCtx CollectionWordsO
 	return nil
 }
 
-func (r *collectionWordsRepo) GetByWords(words []string, wordsCtx CollectionWordsOperationCtx) ([]models.Word, error) {
+// UpdateCollectionId moves the word to another collection of the same user
+func (r *collectionWordsRepo) UpdateCollectionId(id string, collectionId uint64, wordsCtx CollectionWordsOperationCtx) error {
+	index, err := r.getIndex(wordsCtx)
+	if err != nil {
+		return err
+	}
+
+	doc := map[string]interface{}{
+		"collection_id": collectionId,
+	}
+
+	ctx := context.Background()
+	_, err = r.client.Update().Index(index.GetName()).Refresh("true").Doc(doc).Id(id).Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAllWords returns all words which are not in trash, unlike GetAll
+// it is not limited by one page of search results
+func (r *collectionWordsRepo) GetAllWords(wordsCtx CollectionWordsOperationCtx) ([]models.Word, error) {
 	index, err := r.getIndex(wordsCtx)
 	if err != nil {
 		return nil, err
 	}
 
+	return r.scrollWords(index.GetName(), excludeDeleted(elastic.NewBoolQuery()), wordsSorters(models.WordsFilter{})...)
+}
+
+// scrollWords reads all words matched by the query page by page
+func (r *collectionWordsRepo) scrollWords(indexName string, query elastic.Query, sorters ...elastic.Sorter) ([]models.Word, error) {
 	ctx := context.Background()
 
-	wordsForSearch := make([]interface{}, len(words))
-	for index, value := range words {
-		wordsForSearch[index] = value
+	scroll := r.client.Scroll(indexName).Query(query).Size(bulkChunkSize).KeepAlive(scrollKeepAlive)
+	if len(sorters) > 0 {
+		scroll = scroll.SortBy(sorters...)
 	}
+	defer scroll.Clear(ctx)
 
-	query := elastic.NewBoolQuery()
+	words := []models.Word{}
+	for {
+		searchResult, err := scroll.Do(ctx)
+		if err == io.EOF {
+			return words, nil
+		}
+		if err != nil {
+			return nil, err
+		}
 
-	q1 := elastic.NewTermsQuery("word", wordsForSearch...)
-	// q2 := elastic.NewMatchQuery("collection_id", collectionId)
+		for _, hit := range searchResult.Hits.Hits {
+			var word ElasticWord
+			err := json.Unmarshal(hit.Source, &word)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read word %s: %w", hit.Id, err)
+			}
 
-	query.Must(q1)
+			words = append(words, word.FromModel(hit.Id))
+		}
+	}
+}
 
-	searchResult, err := r.client.Search().Index(index.GetName()).Query(query).Do(ctx)
+// GetByWords returns words equal to one of passed words ignoring case,
+// words are looked up by chunks so long lists don't exceed limits of bool query
+func (r *collectionWordsRepo) GetByWords(words []string, wordsCtx CollectionWordsOperationCtx) ([]models.Word, error) {
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	index, err := r.getIndex(wordsCtx)
 	if err != nil {
 		return nil, err
 	}
+
 	var findedWords []models.Word
-	for _, word := range searchResult.Each(reflect.TypeOf(ElasticWord{})) {
-		word, ok := word.(ElasticWord)
-		if !ok {
-			continue
+	for from := 0; from < len(words); from += getByWordsChunkSize {
+		to := from + getByWordsChunkSize
+		if to > len(words) {
+			to = len(words)
 		}
 
-		findedWords = append(findedWords, models.Word{
-			CollectionId: word.CollectionId,
-			Word:         word.Word,
-			Translation:  word.Translation,
-			PartOfSpeech: word.PartOfSpeech,
-			Scentance:    word.Scentance,
-			CreatedAt:    word.CreatedAt,
-		})
-	}
+		query := elastic.NewBoolQuery()
 
-	if len(words) == 0 {
-		return nil, nil
+		wordsForSearch := map[string]bool{}
+		for _, value := range words[from:to] {
+			wordsForSearch[strings.ToLower(strings.TrimSpace(value))] = true
+			query.Should(elastic.NewMatchPhraseQuery("word", value))
+		}
+		query.MinimumNumberShouldMatch(1)
+		excludeDeleted(query)
+
+		chunkWords, err := r.scrollWords(index.GetName(), query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, word := range chunkWords {
+			// phrase query matches longer words too, so check exact value
+			if !wordsForSearch[strings.ToLower(strings.TrimSpace(word.Word))] {
+				continue
+			}
+
+			findedWords = append(findedWords, word)
+		}
 	}
 
 	return findedWords, nil
@@ -366,7 +577,7 @@ func (r *collectionWordsRepo) Search(settings models.SearchSettings, wordsCtx Co
 
 	query := elastic.NewBoolQuery()
 
-	q1 := elastic.NewWildcardQuery(settings.SearchBy, "*"+settings.TextForSearch+"*")
+	q1 := searchTextQuery(settings)
 
 	query.Must(q1)
 
@@ -380,7 +591,14 @@ func (r *collectionWordsRepo) Search(settings models.SearchSettings, wordsCtx Co
 		query.Must(q3)
 	}
 
-	searchResult, err := r.client.Search().Index(index.GetName()).Query(query).Do(ctx)
+	applyWordsFilter(query, settings.WordsFilter)
+
+	search := r.client.Search().Index(index.GetName()).Query(query)
+	if settings.SortBy != "" {
+		search = search.SortBy(wordsSorters(settings.WordsFilter)...)
+	}
+
+	searchResult, err := search.Do(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -392,15 +610,7 @@ func (r *collectionWordsRepo) Search(settings models.SearchSettings, wordsCtx Co
 			continue
 		}
 
-		words = append(words, models.Word{
-			Id:           hit.Id,
-			CollectionId: word.CollectionId,
-			Word:         word.Word,
-			Translation:  word.Translation,
-			PartOfSpeech: word.PartOfSpeech,
-			Scentance:    word.Scentance,
-			CreatedAt:    word.CreatedAt,
-		})
+		words = append(words, word.FromModel(hit.Id))
 	}
 
 	if len(words) == 0 {
@@ -426,7 +636,7 @@ func (r *collectionWordsRepo) SearchOnCollections(settings models.SearchSettings
 
 	query := elastic.NewBoolQuery()
 
-	q1 := elastic.NewWildcardQuery(settings.SearchBy, "*"+settings.TextForSearch+"*")
+	q1 := searchTextQuery(settings)
 
 	query.Must(q1)
 
@@ -439,6 +649,7 @@ func (r *collectionWordsRepo) SearchOnCollections(settings models.SearchSettings
 
 		query.Must(q3)
 	}
+	excludeDeleted(query)
 
 	searchResult, err := r.client.Search().Index(indices...).Query(query).Do(ctx)
 	if err != nil {
@@ -452,15 +663,7 @@ func (r *collectionWordsRepo) SearchOnCollections(settings models.SearchSettings
 			continue
 		}
 
-		words = append(words, models.Word{
-			Id:           hit.Id,
-			CollectionId: word.CollectionId,
-			Word:         word.Word,
-			Translation:  word.Translation,
-			PartOfSpeech: word.PartOfSpeech,
-			Scentance:    word.Scentance,
-			CreatedAt:    word.CreatedAt,
-		})
+		words = append(words, word.FromModel(hit.Id))
 	}
 
 	if len(words) == 0 {
@@ -484,7 +687,7 @@ func (r *collectionWordsRepo) GetAllWordsCount(userIds []uint64) (int64, error)
 
 	ctx := context.Background()
 
-	countOfAllWords, err := r.client.Count().Index(indices...).Do(ctx)
+	countOfAllWords, err := r.client.Count().Index(indices...).Query(excludeDeleted(elastic.NewBoolQuery())).Do(ctx)
 	if err != nil {
 		return 0, err
 	}
@@ -492,7 +695,7 @@ func (r *collectionWordsRepo) GetAllWordsCount(userIds []uint64) (int64, error)
 	return countOfAllWords, nil
 }
 
-func (r *collectionWordsRepo) GetCountOfWordsPerTime(userIds []uint64, time string) ([]models.WordsAddedPerTime, error) {
+func (r *collectionWordsRepo) GetCountOfWordsPerTime(userIds []uint64, time string, timezone string) ([]models.WordsAddedPerTime, error) {
 	var indices []string
 
 	for _, uId := range userIds {
@@ -506,9 +709,9 @@ func (r *collectionWordsRepo) GetCountOfWordsPerTime(userIds []uint64, time stri
 
 	ctx := context.Background()
 
-	aggregation := elastic.NewDateHistogramAggregation().CalendarInterval(time).Field("created_at")
+	aggregation := elastic.NewDateHistogramAggregation().CalendarInterval(time).Field("created_at").TimeZone(timezone)
 
-	result, err := r.client.Search().Index(indices...).Size(0).Aggregation("words_added_per_time", aggregation).Do(ctx)
+	result, err := r.client.Search().Index(indices...).Query(excludeDeleted(elastic.NewBoolQuery())).Size(0).Aggregation("words_added_per_time", aggregation).Do(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -526,6 +729,590 @@ func (r *collectionWordsRepo) GetCountOfWordsPerTime(userIds []uint64, time stri
 	return responses, nil
 }
 
+func (r *collectionWordsRepo) GetDue(dueTo time.Time, size uint64, wordsCtx CollectionWordsOperationCtx) ([]models.Word, uint64, error) {
+	index, err := r.getIndex(wordsCtx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ctx := context.Background()
+
+	// word is due when its due date passed or it was never reviewed
+	query := elastic.NewBoolQuery()
+	query.Should(elastic.NewRangeQuery("progress.due_date").Lte(dueTo))
+	query.Should(elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery("progress.due_date")))
+	query.MinimumNumberShouldMatch(1)
+	excludeDeleted(query)
+
+	sort := elastic.NewFieldSort("progress.due_date").Asc().Missing("_first").UnmappedType("date")
+
+	searchResult, err := r.client.Search().Index(index.GetName()).Query(query).SortBy(sort, elastic.NewFieldSort("created_at").Asc()).Size(int(size)).Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	totalHits := uint64(searchResult.Hits.TotalHits.Value)
+
+	var words []models.Word
+	for _, hit := range searchResult.Hits.Hits {
+		var word ElasticWord
+		err := json.Unmarshal(hit.Source, &word)
+		if err != nil {
+			continue
+		}
+
+		words = append(words, word.FromModel(hit.Id))
+	}
+
+	return words, totalHits, nil
+}
+
+func (r *collectionWordsRepo) UpdateProgress(id string, progress models.WordProgress, wordsCtx CollectionWordsOperationCtx) error {
+	index, err := r.getIndex(wordsCtx)
+	if err != nil {
+		return err
+	}
+
+	doc := map[string]interface{}{
+		"progress": ToElasticWordProgress(progress),
+	}
+
+	ctx := context.Background()
+	_, err = r.client.Update().Index(index.GetName()).Refresh("true").Doc(doc).Id(id).Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *collectionWordsRepo) GetCountOfWordsPerCollection(userId uint64) ([]models.WordsPerCollection, error) {
+	index, err := r.getIndex(CollectionWordsOperationCtx{UserId: userId})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	aggregation := elastic.NewTermsAggregation().Field("collection_id").Size(1000)
+
+	result, err := r.client.Search().Index(index.GetName()).Query(excludeDeleted(elastic.NewBoolQuery())).Size(0).Aggregation("words_per_collection", aggregation).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregationResult, ok := result.Aggregations.Terms("words_per_collection")
+	if !ok {
+		return nil, errors.New("missing words per collection aggregation")
+	}
+
+	responses := []models.WordsPerCollection{}
+	for _, b := range aggregationResult.Buckets {
+		collectionId, ok := b.Key.(float64)
+		if !ok {
+			continue
+		}
+
+		responses = append(responses, models.WordsPerCollection{
+			CollectionId: uint64(collectionId),
+			Count:        uint64(b.DocCount),
+		})
+	}
+
+	return responses, nil
+}
+
+// GetCountOfWordsInCollections counts words of collections owned by different users with one request
+func (r *collectionWordsRepo) GetCountOfWordsInCollections(collections []CollectionWordsOperationCtx) ([]models.WordsPerCollection, error) {
+	responses := []models.WordsPerCollection{}
+	if len(collections) == 0 {
+		return responses, nil
+	}
+
+	var indices []string
+	seenIndices := map[string]bool{}
+	collectionIds := make([]interface{}, 0, len(collections))
+	for _, c := range collections {
+		index, err := r.getIndex(CollectionWordsOperationCtx{UserId: c.UserId})
+		if err != nil {
+			return nil, err
+		}
+
+		if !seenIndices[index.GetName()] {
+			seenIndices[index.GetName()] = true
+			indices = append(indices, index.GetName())
+		}
+		collectionIds = append(collectionIds, c.CollectionId)
+	}
+
+	ctx := context.Background()
+
+	query := excludeDeleted(elastic.NewBoolQuery()).Filter(elastic.NewTermsQuery("collection_id", collectionIds...))
+	aggregation := elastic.NewTermsAggregation().Field("collection_id").Size(len(collections))
+
+	result, err := r.client.Search().Index(indices...).Query(query).Size(0).Aggregation("words_per_collection", aggregation).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregationResult, ok := result.Aggregations.Terms("words_per_collection")
+	if !ok {
+		return nil, errors.New("missing words per collection aggregation")
+	}
+
+	for _, b := range aggregationResult.Buckets {
+		collectionId, ok := b.Key.(float64)
+		if !ok {
+			continue
+		}
+
+		responses = append(responses, models.WordsPerCollection{
+			CollectionId: uint64(collectionId),
+			Count:        uint64(b.DocCount),
+		})
+	}
+
+	return responses, nil
+}
+
+func (r *collectionWordsRepo) GetCountOfWordsPerPartOfSpeech(userId uint64) ([]models.WordsPerPartOfSpeech, error) {
+	index, err := r.getIndex(CollectionWordsOperationCtx{UserId: userId})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	aggregation := elastic.NewTermsAggregation().Field("part_of_speech.keyword").Size(100).Missing("")
+
+	result, err := r.client.Search().Index(index.GetName()).Query(excludeDeleted(elastic.NewBoolQuery())).Size(0).Aggregation("words_per_part_of_speech", aggregation).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregationResult, ok := result.Aggregations.Terms("words_per_part_of_speech")
+	if !ok {
+		return nil, errors.New("missing words per part of speech aggregation")
+	}
+
+	responses := []models.WordsPerPartOfSpeech{}
+	for _, b := range aggregationResult.Buckets {
+		partOfSpeech, _ := b.Key.(string)
+
+		responses = append(responses, models.WordsPerPartOfSpeech{
+			PartOfSpeech: partOfSpeech,
+			Count:        uint64(b.DocCount),
+		})
+	}
+
+	return responses, nil
+}
+
+// GetCountOfWordsPerDay returns words added per every day in range including empty days,
+// days are bucketed in the passed timezone
+func (r *collectionWordsRepo) GetCountOfWordsPerDay(userId uint64, from, to time.Time, timezone string) ([]models.WordsAddedPerTime, error) {
+	index, err := r.getIndex(CollectionWordsOperationCtx{UserId: userId})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	const dayFormat = "yyyy-MM-dd"
+
+	query := excludeDeleted(elastic.NewBoolQuery().Filter(elastic.NewRangeQuery("created_at").Gte(from).Lte(to)))
+
+	aggregation := elastic.NewDateHistogramAggregation().
+		CalendarInterval("day").
+		Field("created_at").
+		Format(dayFormat).
+		TimeZone(timezone).
+		MinDocCount(0).
+		ExtendedBounds(from.In(to.Location()).Format("2006-01-02"), to.Format("2006-01-02"))
+
+	result, err := r.client.Search().Index(index.GetName()).Query(query).Size(0).Aggregation("words_added_per_day", aggregation).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregationResult, ok := result.Aggregations.DateHistogram("words_added_per_day")
+	if !ok {
+		return nil, errors.New("missing words per day aggregation")
+	}
+
+	responses := []models.WordsAddedPerTime{}
+	for _, a := range aggregationResult.Buckets {
+		if a.KeyAsString == nil {
+			continue
+		}
+
+		responses = append(responses, models.WordsAddedPerTime{
+			Count: uint64(a.DocCount),
+			Date:  *a.KeyAsString,
+		})
+	}
+
+	return responses, nil
+}
+
+// GetTags returns all tags used in user collections with words count
+func (r *collectionWordsRepo) GetTags(userId uint64) ([]models.TagCount, error) {
+	index, err := r.getIndex(CollectionWordsOperationCtx{UserId: userId})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	aggregation := elastic.NewTermsAggregation().Field("tags").Size(1000).OrderByKeyAsc()
+
+	result, err := r.client.Search().Index(index.GetName()).Query(excludeDeleted(elastic.NewBoolQuery())).Size(0).Aggregation("tags", aggregation).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregationResult, ok := result.Aggregations.Terms("tags")
+	if !ok {
+		return nil, errors.New("missing tags aggregation")
+	}
+
+	tags := []models.TagCount{}
+	for _, b := range aggregationResult.Buckets {
+		name, ok := b.Key.(string)
+		if !ok {
+			continue
+		}
+
+		tags = append(tags, models.TagCount{
+			Name:  name,
+			Count: uint64(b.DocCount),
+		})
+	}
+
+	return tags, nil
+}
+
+// ReplaceTags replaces tags with the new tag in all user collections,
+// so it renames or merges tags. Empty new tag deletes tags.
+// Returns count of updated words.
+func (r *collectionWordsRepo) ReplaceTags(userId uint64, tags []string, newTag string) (uint64, error) {
+	index, err := r.getIndex(CollectionWordsOperationCtx{UserId: userId})
+	if err != nil {
+		return 0, err
+	}
+
+	ctx := context.Background()
+
+	tags = models.NormalizeTags(tags)
+	newTag = strings.ToLower(strings.TrimSpace(newTag))
+
+	tagsForSearch := make([]interface{}, len(tags))
+	for index, value := range tags {
+		tagsForSearch[index] = value
+	}
+
+	// words of collections in trash keep their tags until collection is restored or purged
+	query := excludeDeletedCollections(elastic.NewBoolQuery().Filter(elastic.NewTermsQuery("tags", tagsForSearch...)))
+
+	script := elastic.NewScript(`
+		def result = new ArrayList();
+		for (tag in ctx._source.tags) {
+			def value = params.tags.contains(tag) ? params.newTag : tag;
+			if (value != null && value != '' && !result.contains(value)) {
+				result.add(value);
+			}
+		}
+		ctx._source.tags = result;`).
+		Params(map[string]interface{}{
+			"tags":   tags,
+			"newTag": newTag,
+		})
+
+	// words changed concurrently are skipped on version conflict,
+	// they still have old tags and are found by the next run
+	var updated uint64
+	for attempt := 0; attempt < replaceTagsAttempts; attempt++ {
+		response, err := r.client.UpdateByQuery(index.GetName()).Query(query).Script(script).ProceedOnVersionConflict().Refresh("true").Do(ctx)
+		if err != nil {
+			return updated, err
+		}
+
+		updated += uint64(response.Updated)
+		if response.VersionConflicts == 0 {
+			return updated, nil
+		}
+	}
+
+	return updated, ErrTagsConflict
+}
+
+// searchTextQuery looks for the text in the field, all
+// translations are checked when searching by translation
+func searchTextQuery(settings models.SearchSettings) elastic.Query {
+	pattern := "*" + settings.TextForSearch + "*"
+
+	if settings.SearchBy != "translation" {
+		return elastic.NewWildcardQuery(settings.SearchBy, pattern)
+	}
+
+	query := elastic.NewBoolQuery()
+	query.Should(elastic.NewWildcardQuery("translation", pattern))
+	query.Should(elastic.NewWildcardQuery("translations", pattern))
+	query.MinimumNumberShouldMatch(1)
+
+	return query
+}
+
+// excludeDeleted skips words moved to trash and words of collections moved to trash
+func excludeDeleted(query *elastic.BoolQuery) *elastic.BoolQuery {
+	return excludeDeletedCollections(query).MustNot(elastic.NewExistsQuery("deleted_at"))
+}
+
+// excludeDeletedCollections skips words of collections moved to trash
+func excludeDeletedCollections(query *elastic.BoolQuery) *elastic.BoolQuery {
+	return query.MustNot(elastic.NewExistsQuery("collection_deleted_at"))
+}
+
+// applyWordsFilter adds filter conditions to the query,
+// words without progress are treated as new, words in trash are skipped
+func applyWordsFilter(query *elastic.BoolQuery, filter models.WordsFilter) {
+	excludeDeleted(query)
+
+	for _, tag := range models.NormalizeTags(filter.Tags) {
+		query.Filter(elastic.NewTermQuery("tags", tag))
+	}
+
+	if len(filter.Mastery) == 0 {
+		return
+	}
+
+	levels := make([]interface{}, len(filter.Mastery))
+	withNew := false
+	for index, value := range filter.Mastery {
+		levels[index] = string(value)
+		if value == models.MasteryLevelNew {
+			withNew = true
+		}
+	}
+
+	masteryQuery := elastic.NewBoolQuery()
+	masteryQuery.Should(elastic.NewTermsQuery("progress.mastery", levels...))
+	if withNew {
+		masteryQuery.Should(elastic.NewBoolQuery().MustNot(elastic.NewExistsQuery("progress.mastery")))
+	}
+	masteryQuery.MinimumNumberShouldMatch(1)
+
+	query.Filter(masteryQuery)
+}
+
+func wordsSorters(filter models.WordsFilter) []elastic.Sorter {
+	ascending := filter.SortOrder == models.SortOrderAsc
+
+	switch filter.SortBy {
+	case models.WordsSortByMastery:
+		// mastery level grows together with review interval
+		intervalSort := elastic.NewFieldSort("progress.interval").Order(ascending).UnmappedType("integer")
+		if ascending {
+			intervalSort = intervalSort.Missing("_first")
+		} else {
+			intervalSort = intervalSort.Missing("_last")
+		}
+
+		return []elastic.Sorter{intervalSort, elastic.NewFieldSort("created_at").Desc()}
+	default:
+		return []elastic.Sorter{elastic.NewFieldSort("created_at").Order(ascending)}
+	}
+}
+
+// GetIdsByCollectionId returns ids of all words of the collection including words in trash,
+// user index is used because collection alias can be already removed
+func (r *collectionWordsRepo) GetIdsByCollectionId(wordsCtx CollectionWordsOperationCtx) ([]string, error) {
+	index, err := r.getIndex(CollectionWordsOperationCtx{UserId: wordsCtx.UserId})
+	if err != nil {
+		return nil, err
+	}
+
+	words, err := r.scrollWords(index.GetName(), elastic.NewTermQuery("collection_id", wordsCtx.CollectionId))
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, w := range words {
+		ids = append(ids, w.Id)
+	}
+
+	return ids, nil
+}
+
+// DeleteByCollectionId removes all words of the collection from user index,
+// user index is used because collection alias can be already removed
+func (r *collectionWordsRepo) DeleteByCollectionId(wordsCtx CollectionWordsOperationCtx) (uint64, error) {
+	index, err := r.getIndex(CollectionWordsOperationCtx{UserId: wordsCtx.UserId})
+	if err != nil {
+		return 0, err
+	}
+
+	ctx := context.Background()
+
+	query := elastic.NewTermQuery("collection_id", wordsCtx.CollectionId)
+
+	response, err := r.client.DeleteByQuery(index.GetName()).Query(query).ProceedOnVersionConflict().Refresh("true").Do(ctx)
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	if len(response.Failures) > 0 {
+		return uint64(response.Deleted), fmt.Errorf("failed to delete %d words of collection", len(response.Failures))
+	}
+
+	return uint64(response.Deleted), nil
+}
+
+// SoftDeleteById moves the word to trash, deleted words are skipped by all searches
+func (r *collectionWordsRepo) SoftDeleteById(id string, deletedAt time.Time, wordsCtx CollectionWordsOperationCtx) error {
+	return r.updateDeletedAt(id, &deletedAt, wordsCtx)
+}
+
+// RestoreById returns the word from trash
+func (r *collectionWordsRepo) RestoreById(id string, wordsCtx CollectionWordsOperationCtx) error {
+	return r.updateDeletedAt(id, nil, wordsCtx)
+}
+
+func (r *collectionWordsRepo) updateDeletedAt(id string, deletedAt *time.Time, wordsCtx CollectionWordsOperationCtx) error {
+	index, err := r.getIndex(wordsCtx)
+	if err != nil {
+		return err
+	}
+
+	doc := map[string]interface{}{
+		"deleted_at": deletedAt,
+	}
+
+	ctx := context.Background()
+	_, err = r.client.Update().Index(index.GetName()).Refresh("true").Doc(doc).Id(id).Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SoftDeleteByCollectionId hides words of collection moved to trash, words keep their own
+// deleted_at, so words deleted before the collection stay in trash after it is restored
+func (r *collectionWordsRepo) SoftDeleteByCollectionId(deletedAt time.Time, wordsCtx CollectionWordsOperationCtx) error {
+	script := elastic.NewScript("ctx._source.collection_deleted_at = params.deletedAt").
+		Params(map[string]interface{}{
+			"deletedAt": deletedAt,
+		})
+
+	return r.updateByCollectionId(script, wordsCtx)
+}
+
+// RestoreByCollectionId returns words of collection restored from trash
+func (r *collectionWordsRepo) RestoreByCollectionId(wordsCtx CollectionWordsOperationCtx) error {
+	return r.updateByCollectionId(elastic.NewScript("ctx._source.remove('collection_deleted_at')"), wordsCtx)
+}
+
+// updateByCollectionId runs the script on all words of the collection in user index
+func (r *collectionWordsRepo) updateByCollectionId(script *elastic.Script, wordsCtx CollectionWordsOperationCtx) error {
+	index, err := r.getIndex(CollectionWordsOperationCtx{UserId: wordsCtx.UserId})
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	query := elastic.NewTermQuery("collection_id", wordsCtx.CollectionId)
+
+	response, err := r.client.UpdateByQuery(index.GetName()).Query(query).Script(script).Refresh("true").Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(response.Failures) > 0 {
+		return fmt.Errorf("failed to update %d words of collection", len(response.Failures))
+	}
+
+	return nil
+}
+
+// GetDeleted returns words in trash from all user collections, recently deleted first
+func (r *collectionWordsRepo) GetDeleted(userId uint64) ([]models.Word, error) {
+	index, err := r.getIndex(CollectionWordsOperationCtx{UserId: userId})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	query := elastic.NewExistsQuery("deleted_at")
+
+	searchResult, err := r.client.Search().Index(index.GetName()).Query(query).SortBy(elastic.NewFieldSort("deleted_at").Desc()).Size(1000).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	words := []models.Word{}
+	for _, hit := range searchResult.Hits.Hits {
+		var word ElasticWord
+		err := json.Unmarshal(hit.Source, &word)
+		if err != nil {
+			return nil, err
+		}
+
+		words = append(words, word.FromModel(hit.Id))
+	}
+
+	return words, nil
+}
+
+// PurgeDeleted permanently removes words which are in trash since the passed time,
+// ids of removed words are returned
+func (r *collectionWordsRepo) PurgeDeleted(userId uint64, deletedBefore time.Time) ([]string, error) {
+	index, err := r.getIndex(CollectionWordsOperationCtx{UserId: userId})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	query := elastic.NewRangeQuery("deleted_at").Lt(deletedBefore)
+
+	words, err := r.scrollWords(index.GetName(), query)
+	if err != nil {
+		if elastic.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, w := range words {
+		ids = append(ids, w.Id)
+	}
+
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	// words restored after they were read are kept
+	deleteQuery := elastic.NewBoolQuery().Filter(query, elastic.NewIdsQuery().Ids(ids...))
+
+	_, err = r.client.DeleteByQuery(index.GetName()).Query(deleteQuery).ProceedOnVersionConflict().Refresh("true").Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
 func (r *collectionWordsRepo) getIndex(ctx CollectionWordsOperationCtx) (*myElastic.CollectionWordsIndex, error) {
 	index, err := myElastic.NewCollectionWordsIndex(myElastic.CollectionWordsIndexContext{UserID: ctx.UserId, CollectionID: ctx.CollectionId})
 	if err != nil {
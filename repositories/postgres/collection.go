@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"errors"
 	"time"
 	"vacabulary/models"
 
@@ -16,6 +17,20 @@ type CollectionModel struct {
 	CreatedAt time.Time `pg:"created_at"`
 	LangFrom  string    `pg:"lang_from"`
 	LangTo    string    `pg:"lang_to"`
+
+	Description string     `pg:"description,use_zero"`
+	IsPublic    bool       `pg:"is_public,use_zero"`
+	PublishedAt *time.Time `pg:"published_at"`
+
+	Color     string `pg:"color,use_zero"`
+	Icon      string `pg:"icon,use_zero"`
+	Archived  bool   `pg:"archived,use_zero"`
+	SortOrder int64  `pg:"sort_order,use_zero"`
+
+	DeletedAt *time.Time `pg:"deleted_at"`
+
+	Scheduler        string   `pg:"scheduler"`
+	LeitnerIntervals []uint64 `pg:"leitner_intervals,array"`
 }
 
 func (u *CollectionModel) FromModel() *models.Collection {
@@ -26,6 +41,22 @@ func (u *CollectionModel) FromModel() *models.Collection {
 		CreatedAt: u.CreatedAt,
 		LangFrom:  u.LangFrom,
 		LangTo:    u.LangTo,
+
+		Description: u.Description,
+		IsPublic:    u.IsPublic,
+		PublishedAt: u.PublishedAt,
+
+		Color:     u.Color,
+		Icon:      u.Icon,
+		Archived:  u.Archived,
+		SortOrder: u.SortOrder,
+
+		DeletedAt: u.DeletedAt,
+
+		SchedulerSettings: models.SchedulerSettings{
+			Type:             u.Scheduler,
+			LeitnerIntervals: u.LeitnerIntervals,
+		},
 	}
 }
 
@@ -37,7 +68,36 @@ func ToCollectionModel(u models.Collection) *CollectionModel {
 		CreatedAt: u.CreatedAt,
 		LangFrom:  u.LangFrom,
 		LangTo:    u.LangTo,
+
+		Description: u.Description,
+		IsPublic:    u.IsPublic,
+		PublishedAt: u.PublishedAt,
+
+		Color:     u.Color,
+		Icon:      u.Icon,
+		Archived:  u.Archived,
+		SortOrder: u.SortOrder,
+
+		DeletedAt: u.DeletedAt,
+
+		Scheduler:        u.SchedulerSettings.Type,
+		LeitnerIntervals: u.SchedulerSettings.LeitnerIntervals,
+	}
+}
+
+// ErrCollectionNameExists is returned when owner already has collection with such name
+var ErrCollectionNameExists = errors.New("collection with such name already exists")
+
+// uniqueViolation is postgres error code of unique constraint violation
+const uniqueViolation = "23505"
+
+func isUniqueViolation(err error) bool {
+	var pgErr pg.Error
+	if errors.As(err, &pgErr) {
+		return pgErr.Field('C') == uniqueViolation
 	}
+
+	return false
 }
 
 type collectionRepo struct {
@@ -48,8 +108,16 @@ type Collections interface {
 	Create(collection models.Collection) (*models.Collection, error)
 	GetByOwnerId(ownerId uint64) ([]models.Collection, error)
 	GetById(id uint64) (*models.Collection, error)
-	GetByName(name string) (*models.Collection, error)
+	GetByOwnerAndName(ownerId uint64, name string) (*models.Collection, error)
 	Update(collection *models.Collection) (*models.Collection, error)
+	UpdateSchedulerSettings(id uint64, settings models.SchedulerSettings) error
+	UpdatePublication(id uint64, isPublic bool, description string, publishedAt *time.Time) error
+	GetPublic(filter models.CatalogFilter, size, page uint64) ([]models.Collection, uint64, error)
+	SoftDeleteById(id uint64, deletedAt time.Time) error
+	Restore(id uint64) error
+	GetDeletedById(id uint64) (*models.Collection, error)
+	GetDeletedByOwnerId(ownerId uint64) ([]models.Collection, error)
+	GetDeletedBefore(deletedBefore time.Time) ([]models.Collection, error)
 	DeleteById(id uint64) error
 	GetAll() ([]models.Collection, error)
 }
@@ -65,6 +133,9 @@ func (r *collectionRepo) Create(collection models.Collection) (*models.Collectio
 
 	_, err := r.db.Model(collectionModel).Insert()
 	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrCollectionNameExists
+		}
 		return nil, err
 	}
 
@@ -75,7 +146,7 @@ func (r *collectionRepo) Create(collection models.Collection) (*models.Collectio
 func (r *collectionRepo) GetByOwnerId(ownerId uint64) ([]models.Collection, error) {
 	var collectionModels []CollectionModel
 
-	err := r.db.Model(&collectionModels).Where("owner_id=?", ownerId).Order("created_at").Select()
+	err := r.db.Model(&collectionModels).Where("owner_id=?", ownerId).Where("deleted_at IS NULL").Order("sort_order", "created_at").Select()
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +161,7 @@ func (r *collectionRepo) GetByOwnerId(ownerId uint64) ([]models.Collection, erro
 
 func (r *collectionRepo) GetById(id uint64) (*models.Collection, error) {
 	collection := CollectionModel{}
-	err := r.db.Model(&collection).Where("id=?", id).First()
+	err := r.db.Model(&collection).Where("id=?", id).Where("deleted_at IS NULL").First()
 	if err != nil {
 		if err == pg.ErrNoRows {
 			return nil, nil
@@ -104,7 +175,7 @@ func (r *collectionRepo) GetById(id uint64) (*models.Collection, error) {
 
 func (r *collectionRepo) GetAll() ([]models.Collection, error) {
 	var collections []CollectionModel
-	err := r.db.Model(&collections).Select()
+	err := r.db.Model(&collections).Where("deleted_at IS NULL").Select()
 	if err != nil {
 		if err == pg.ErrNoRows {
 			return nil, nil
@@ -120,9 +191,9 @@ func (r *collectionRepo) GetAll() ([]models.Collection, error) {
 	return collectionsRes, nil
 }
 
-func (r *collectionRepo) GetByName(name string) (*models.Collection, error) {
+func (r *collectionRepo) GetByOwnerAndName(ownerId uint64, name string) (*models.Collection, error) {
 	collection := CollectionModel{}
-	err := r.db.Model(&collection).Where("name=?", name).First()
+	err := r.db.Model(&collection).Where("owner_id=?", ownerId).Where("name=?", name).Where("deleted_at IS NULL").First()
 	if err != nil {
 		if err == pg.ErrNoRows {
 			return nil, nil
@@ -137,11 +208,14 @@ func (r *collectionRepo) GetByName(name string) (*models.Collection, error) {
 func (r *collectionRepo) Update(collection *models.Collection) (*models.Collection, error) {
 	model := ToCollectionModel(*collection)
 
-	_, err := r.db.Model(model).Where("id=?", model.ID).Column("name").Update()
+	_, err := r.db.Model(model).Where("id=?", model.ID).Column("name", "description", "color", "icon", "archived", "sort_order").Update()
 	if err != nil {
 		if err == pg.ErrNoRows {
 			return nil, nil
 		}
+		if isUniqueViolation(err) {
+			return nil, ErrCollectionNameExists
+		}
 		return nil, err
 	}
 
@@ -149,6 +223,150 @@ func (r *collectionRepo) Update(collection *models.Collection) (*models.Collecti
 	return updatedCollection, nil
 }
 
+func (r *collectionRepo) UpdateSchedulerSettings(id uint64, settings models.SchedulerSettings) error {
+	model := CollectionModel{
+		Scheduler:        settings.Type,
+		LeitnerIntervals: settings.LeitnerIntervals,
+	}
+
+	_, err := r.db.Model(&model).Where("id=?", id).Column("scheduler", "leitner_intervals").Update()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *collectionRepo) UpdatePublication(id uint64, isPublic bool, description string, publishedAt *time.Time) error {
+	model := CollectionModel{
+		IsPublic:    isPublic,
+		Description: description,
+		PublishedAt: publishedAt,
+	}
+
+	_, err := r.db.Model(&model).Where("id=?", id).Column("is_public", "description", "published_at").Update()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetPublic returns page of public collections and count of all matched collections,
+// all collections are returned when size is 0. Collections are sorted by name
+// or by publication date, sort by words is applied by caller.
+func (r *collectionRepo) GetPublic(filter models.CatalogFilter, size, page uint64) ([]models.Collection, uint64, error) {
+	var collectionModels []CollectionModel
+
+	query := r.db.Model(&collectionModels).Where("is_public").Where("deleted_at IS NULL")
+	if filter.LangFrom != "" {
+		query = query.Where("lang_from=?", filter.LangFrom)
+	}
+	if filter.LangTo != "" {
+		query = query.Where("lang_to=?", filter.LangTo)
+	}
+	if filter.Name != "" {
+		query = query.Where("name ILIKE ?", "%"+filter.Name+"%")
+	}
+
+	if filter.SortBy == models.CatalogSortByName {
+		query = query.OrderExpr("lower(name) ASC")
+	} else {
+		query = query.Order("published_at DESC")
+	}
+	query = query.Order("id ASC")
+
+	if size > 0 {
+		query = query.Limit(int(size)).Offset(int(size * (page - 1)))
+	}
+
+	total, err := query.SelectAndCount()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	collections := []models.Collection{}
+	for _, c := range collectionModels {
+		collections = append(collections, *c.FromModel())
+	}
+
+	return collections, uint64(total), nil
+}
+
+// SoftDeleteById moves collection to trash, collections in trash are skipped by all getters
+func (r *collectionRepo) SoftDeleteById(id uint64, deletedAt time.Time) error {
+	_, err := r.db.Model(&CollectionModel{}).Set("deleted_at=?", deletedAt).Where("id=?", id).Update()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Restore returns collection from trash, it fails when owner already has collection with such name
+func (r *collectionRepo) Restore(id uint64) error {
+	_, err := r.db.Model(&CollectionModel{}).Set("deleted_at=NULL").Where("id=?", id).Update()
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrCollectionNameExists
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *collectionRepo) GetDeletedById(id uint64) (*models.Collection, error) {
+	collection := CollectionModel{}
+	err := r.db.Model(&collection).Where("id=?", id).Where("deleted_at IS NOT NULL").First()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return collection.FromModel(), nil
+}
+
+func (r *collectionRepo) GetDeletedByOwnerId(ownerId uint64) ([]models.Collection, error) {
+	var collectionModels []CollectionModel
+
+	err := r.db.Model(&collectionModels).Where("owner_id=?", ownerId).Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Select()
+	if err != nil {
+		return nil, err
+	}
+
+	collections := []models.Collection{}
+	for _, c := range collectionModels {
+		collections = append(collections, *c.FromModel())
+	}
+
+	return collections, nil
+}
+
+func (r *collectionRepo) GetDeletedBefore(deletedBefore time.Time) ([]models.Collection, error) {
+	var collectionModels []CollectionModel
+
+	err := r.db.Model(&collectionModels).Where("deleted_at<?", deletedBefore).Select()
+	if err != nil {
+		return nil, err
+	}
+
+	collections := []models.Collection{}
+	for _, c := range collectionModels {
+		collections = append(collections, *c.FromModel())
+	}
+
+	return collections, nil
+}
+
 func (r *collectionRepo) DeleteById(id uint64) error {
 	_, err := r.db.Model(&CollectionModel{}).Where("id=?", id).Delete()
 	if err != nil {
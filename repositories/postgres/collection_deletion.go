@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"time"
+	"vacabulary/models"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type CollectionDeletionModel struct {
+	tableName struct{} `pg:"collection_deletions"`
+
+	ID              uint64     `pg:"id"`
+	CollectionID    uint64     `pg:"collection_id"`
+	CollectionName  string     `pg:"collection_name"`
+	OwnerID         uint64     `pg:"owner_id"`
+	Status          string     `pg:"status"`
+	Attempts        uint64     `pg:"attempts,use_zero"`
+	Error           string     `pg:"error,use_zero"`
+	MembersRemoved  uint64     `pg:"members_removed,use_zero"`
+	SessionsRemoved uint64     `pg:"sessions_removed,use_zero"`
+	WordsRemoved    uint64     `pg:"words_removed,use_zero"`
+	AliasRemoved    bool       `pg:"alias_removed,use_zero"`
+	CreatedAt       time.Time  `pg:"created_at"`
+	FinishedAt      *time.Time `pg:"finished_at"`
+}
+
+func (m *CollectionDeletionModel) FromModel() models.CollectionDeletion {
+	return models.CollectionDeletion{
+		Id:              m.ID,
+		CollectionId:    m.CollectionID,
+		CollectionName:  m.CollectionName,
+		OwnerId:         m.OwnerID,
+		Status:          m.Status,
+		Attempts:        m.Attempts,
+		Error:           m.Error,
+		MembersRemoved:  m.MembersRemoved,
+		SessionsRemoved: m.SessionsRemoved,
+		WordsRemoved:    m.WordsRemoved,
+		AliasRemoved:    m.AliasRemoved,
+		CreatedAt:       m.CreatedAt,
+		FinishedAt:      m.FinishedAt,
+	}
+}
+
+func ToCollectionDeletionModel(d models.CollectionDeletion) *CollectionDeletionModel {
+	return &CollectionDeletionModel{
+		ID:              d.Id,
+		CollectionID:    d.CollectionId,
+		CollectionName:  d.CollectionName,
+		OwnerID:         d.OwnerId,
+		Status:          d.Status,
+		Attempts:        d.Attempts,
+		Error:           d.Error,
+		MembersRemoved:  d.MembersRemoved,
+		SessionsRemoved: d.SessionsRemoved,
+		WordsRemoved:    d.WordsRemoved,
+		AliasRemoved:    d.AliasRemoved,
+		CreatedAt:       d.CreatedAt,
+		FinishedAt:      d.FinishedAt,
+	}
+}
+
+type collectionDeletionRepo struct {
+	db *pg.DB
+}
+
+type CollectionDeletions interface {
+	DeleteCollection(collection models.Collection, createdAt time.Time) (*models.CollectionDeletion, error)
+	GetById(id uint64) (*models.CollectionDeletion, error)
+	GetByOwnerId(ownerId uint64) ([]models.CollectionDeletion, error)
+	GetUnfinished(createdBefore time.Time, maxAttempts uint64) ([]models.CollectionDeletion, error)
+	Update(deletion models.CollectionDeletion) error
+}
+
+func NewCollectionDeletionsRepo(db *pg.DB) CollectionDeletions {
+	return &collectionDeletionRepo{
+		db: db,
+	}
+}
+
+// DeleteCollection removes collection row with its members and sessions
+// and creates pending deletion for the data stored in elasticsearch
+func (r *collectionDeletionRepo) DeleteCollection(collection models.Collection, createdAt time.Time) (*models.CollectionDeletion, error) {
+	deletionModel := ToCollectionDeletionModel(models.CollectionDeletion{
+		CollectionId:   collection.Id,
+		CollectionName: collection.Name,
+		OwnerId:        collection.OwnerId,
+		Status:         models.DeletionStatusPending,
+		CreatedAt:      createdAt,
+	})
+
+	err := r.db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		membersCount, err := tx.Model(&CollectionMemberModel{}).Where("collection_id=?", collection.Id).Count()
+		if err != nil {
+			return err
+		}
+
+		sessionsCount, err := tx.Model(&StudySessionModel{}).Where("collection_id=?", collection.Id).Count()
+		if err != nil {
+			return err
+		}
+
+		// members and sessions are removed by foreign key cascade
+		_, err = tx.Model(&CollectionModel{}).Where("id=?", collection.Id).Delete()
+		if err != nil {
+			return err
+		}
+
+		deletionModel.MembersRemoved = uint64(membersCount)
+		deletionModel.SessionsRemoved = uint64(sessionsCount)
+
+		_, err = tx.Model(deletionModel).Insert()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deletion := deletionModel.FromModel()
+	return &deletion, nil
+}
+
+func (r *collectionDeletionRepo) GetById(id uint64) (*models.CollectionDeletion, error) {
+	deletionModel := CollectionDeletionModel{}
+	err := r.db.Model(&deletionModel).Where("id=?", id).First()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	deletion := deletionModel.FromModel()
+	return &deletion, nil
+}
+
+func (r *collectionDeletionRepo) GetByOwnerId(ownerId uint64) ([]models.CollectionDeletion, error) {
+	var deletionModels []CollectionDeletionModel
+
+	err := r.db.Model(&deletionModels).Where("owner_id=?", ownerId).Order("created_at DESC").Select()
+	if err != nil {
+		return nil, err
+	}
+
+	deletions := []models.CollectionDeletion{}
+	for _, d := range deletionModels {
+		deletions = append(deletions, d.FromModel())
+	}
+
+	return deletions, nil
+}
+
+// GetUnfinished returns failed deletions and pending ones created before the time,
+// pending deletion created later can be still running by request
+func (r *collectionDeletionRepo) GetUnfinished(createdBefore time.Time, maxAttempts uint64) ([]models.CollectionDeletion, error) {
+	var deletionModels []CollectionDeletionModel
+
+	err := r.db.Model(&deletionModels).
+		WhereGroup(func(q *pg.Query) (*pg.Query, error) {
+			q = q.WhereOr("status=?", models.DeletionStatusFailed).
+				WhereOr("status=? AND created_at<?", models.DeletionStatusPending, createdBefore)
+			return q, nil
+		}).
+		Where("attempts<?", maxAttempts).
+		Order("created_at").
+		Select()
+	if err != nil {
+		return nil, err
+	}
+
+	deletions := []models.CollectionDeletion{}
+	for _, d := range deletionModels {
+		deletions = append(deletions, d.FromModel())
+	}
+
+	return deletions, nil
+}
+
+func (r *collectionDeletionRepo) Update(deletion models.CollectionDeletion) error {
+	model := ToCollectionDeletionModel(deletion)
+
+	_, err := r.db.Model(model).
+		Where("id=?", model.ID).
+		Column("status", "attempts", "error", "words_removed", "alias_removed", "finished_at").
+		Update()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"time"
+	"vacabulary/models"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type CollectionMemberModel struct {
+	tableName struct{} `pg:"collection_members"`
+
+	ID           uint64           `pg:"id"`
+	CollectionID uint64           `pg:"collection_id"`
+	UserID       uint64           `pg:"user_id"`
+	Role         string           `pg:"role"`
+	Status       string           `pg:"status"`
+	InvitedBy    uint64           `pg:"invited_by"`
+	CreatedAt    time.Time        `pg:"created_at"`
+	AcceptedAt   *time.Time       `pg:"accepted_at"`
+	User         *UserModel       `pg:"rel:has-one,fk:user_id"`
+	Collection   *CollectionModel `pg:"rel:has-one,fk:collection_id"`
+}
+
+func (m *CollectionMemberModel) FromModel() models.CollectionMember {
+	member := models.CollectionMember{
+		Id:           m.ID,
+		CollectionId: m.CollectionID,
+		UserId:       m.UserID,
+		Role:         models.CollectionRole(m.Role),
+		Status:       m.Status,
+		InvitedBy:    m.InvitedBy,
+		CreatedAt:    m.CreatedAt,
+		AcceptedAt:   m.AcceptedAt,
+	}
+
+	if m.User != nil {
+		user := m.User.FromModel()
+		member.User = &user
+	}
+
+	if m.Collection != nil {
+		member.Collection = m.Collection.FromModel()
+	}
+
+	return member
+}
+
+func ToCollectionMemberModel(m models.CollectionMember) *CollectionMemberModel {
+	return &CollectionMemberModel{
+		ID:           m.Id,
+		CollectionID: m.CollectionId,
+		UserID:       m.UserId,
+		Role:         string(m.Role),
+		Status:       m.Status,
+		InvitedBy:    m.InvitedBy,
+		CreatedAt:    m.CreatedAt,
+		AcceptedAt:   m.AcceptedAt,
+	}
+}
+
+type collectionMemberRepo struct {
+	db *pg.DB
+}
+
+type CollectionMembers interface {
+	Create(member models.CollectionMember) (*models.CollectionMember, error)
+	GetById(id uint64) (*models.CollectionMember, error)
+	GetByCollectionId(collectionId uint64) ([]models.CollectionMember, error)
+	GetByCollectionAndUser(collectionId uint64, userId uint64) (*models.CollectionMember, error)
+	GetByUserId(userId uint64, status string) ([]models.CollectionMember, error)
+	Accept(id uint64, acceptedAt time.Time) error
+	UpdateRole(id uint64, role models.CollectionRole) error
+	DeleteById(id uint64) error
+}
+
+func NewCollectionMembersRepo(db *pg.DB) CollectionMembers {
+	return &collectionMemberRepo{
+		db: db,
+	}
+}
+
+func (r *collectionMemberRepo) Create(member models.CollectionMember) (*models.CollectionMember, error) {
+	memberModel := ToCollectionMemberModel(member)
+
+	_, err := r.db.Model(memberModel).Insert()
+	if err != nil {
+		return nil, err
+	}
+
+	createdMember := memberModel.FromModel()
+	return &createdMember, nil
+}
+
+func (r *collectionMemberRepo) GetById(id uint64) (*models.CollectionMember, error) {
+	member := CollectionMemberModel{}
+	err := r.db.Model(&member).Relation("Collection").Where("collection_member_model.id=?", id).First()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	findedMember := member.FromModel()
+	return &findedMember, nil
+}
+
+func (r *collectionMemberRepo) GetByCollectionId(collectionId uint64) ([]models.CollectionMember, error) {
+	var memberModels []CollectionMemberModel
+
+	err := r.db.Model(&memberModels).Relation("User").Where("collection_member_model.collection_id=?", collectionId).Order("collection_member_model.created_at").Select()
+	if err != nil {
+		return nil, err
+	}
+
+	members := []models.CollectionMember{}
+	for _, m := range memberModels {
+		members = append(members, m.FromModel())
+	}
+
+	return members, nil
+}
+
+func (r *collectionMemberRepo) GetByCollectionAndUser(collectionId uint64, userId uint64) (*models.CollectionMember, error) {
+	member := CollectionMemberModel{}
+	err := r.db.Model(&member).Where("collection_id=?", collectionId).Where("user_id=?", userId).First()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	findedMember := member.FromModel()
+	return &findedMember, nil
+}
+
+// GetByUserId returns user memberships with collections, all statuses are returned for empty status
+func (r *collectionMemberRepo) GetByUserId(userId uint64, status string) ([]models.CollectionMember, error) {
+	var memberModels []CollectionMemberModel
+
+	// collections in trash are not shared
+	query := r.db.Model(&memberModels).Relation("Collection").
+		Where("collection_member_model.user_id=?", userId).
+		Where("collection.deleted_at IS NULL")
+	if status != "" {
+		query = query.Where("collection_member_model.status=?", status)
+	}
+
+	err := query.Order("collection_member_model.created_at").Select()
+	if err != nil {
+		return nil, err
+	}
+
+	members := []models.CollectionMember{}
+	for _, m := range memberModels {
+		members = append(members, m.FromModel())
+	}
+
+	return members, nil
+}
+
+func (r *collectionMemberRepo) Accept(id uint64, acceptedAt time.Time) error {
+	_, err := r.db.Model(&CollectionMemberModel{}).
+		Set("status=?", models.MemberStatusAccepted).
+		Set("accepted_at=?", acceptedAt).
+		Where("id=?", id).
+		Update()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *collectionMemberRepo) UpdateRole(id uint64, role models.CollectionRole) error {
+	_, err := r.db.Model(&CollectionMemberModel{}).Set("role=?", string(role)).Where("id=?", id).Update()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *collectionMemberRepo) DeleteById(id uint64) error {
+	_, err := r.db.Model(&CollectionMemberModel{}).Where("id=?", id).Delete()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
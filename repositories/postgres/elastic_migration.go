@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type ElasticMigrationModel struct {
+	tableName struct{} `pg:"elastic_migrations"`
+
+	Version   uint64    `pg:"version,pk"`
+	AppliedAt time.Time `pg:"applied_at"`
+}
+
+type elasticMigrationRepo struct {
+	db *pg.DB
+}
+
+// ElasticMigrations keeps versions of user indices which were applied to all existing users
+type ElasticMigrations interface {
+	IsApplied(version uint64) (bool, error)
+	Create(version uint64, appliedAt time.Time) error
+}
+
+func NewElasticMigrationsRepo(db *pg.DB) ElasticMigrations {
+	return &elasticMigrationRepo{
+		db: db,
+	}
+}
+
+func (r *elasticMigrationRepo) IsApplied(version uint64) (bool, error) {
+	return r.db.Model(&ElasticMigrationModel{}).Where("version=?", version).Exists()
+}
+
+func (r *elasticMigrationRepo) Create(version uint64, appliedAt time.Time) error {
+	_, err := r.db.Model(&ElasticMigrationModel{Version: version, AppliedAt: appliedAt}).OnConflict("DO NOTHING").Insert()
+	return err
+}
@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+	"vacabulary/models"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type ExportJobModel struct {
+	tableName struct{} `pg:"export_jobs"`
+
+	ID            uint64     `pg:"id"`
+	CollectionID  uint64     `pg:"collection_id"`
+	OwnerID       uint64     `pg:"owner_id"`
+	UserID        uint64     `pg:"user_id"`
+	Format        string     `pg:"format"`
+	Options       string     `pg:"options"`
+	Status        string     `pg:"status"`
+	Attempts      uint64     `pg:"attempts,use_zero"`
+	Error         string     `pg:"error,use_zero"`
+	FileKey       string     `pg:"file_key,use_zero"`
+	FileName      string     `pg:"file_name,use_zero"`
+	FileSize      uint64     `pg:"file_size,use_zero"`
+	CreatedAt     time.Time  `pg:"created_at"`
+	StartedAt     *time.Time `pg:"started_at"`
+	FinishedAt    *time.Time `pg:"finished_at"`
+	ExpiresAt     *time.Time `pg:"expires_at"`
+	NextAttemptAt *time.Time `pg:"next_attempt_at"`
+}
+
+func (m *ExportJobModel) FromModel() models.ExportJob {
+	job := models.ExportJob{
+		Id:            m.ID,
+		CollectionId:  m.CollectionID,
+		OwnerId:       m.OwnerID,
+		UserId:        m.UserID,
+		Format:        m.Format,
+		Status:        m.Status,
+		Attempts:      m.Attempts,
+		Error:         m.Error,
+		FileKey:       m.FileKey,
+		FileName:      m.FileName,
+		FileSize:      m.FileSize,
+		CreatedAt:     m.CreatedAt,
+		StartedAt:     m.StartedAt,
+		FinishedAt:    m.FinishedAt,
+		ExpiresAt:     m.ExpiresAt,
+		NextAttemptAt: m.NextAttemptAt,
+	}
+
+	if m.Options != "" {
+		job.Options = json.RawMessage(m.Options)
+	}
+
+	return job
+}
+
+func ToExportJobModel(j models.ExportJob) *ExportJobModel {
+	return &ExportJobModel{
+		ID:            j.Id,
+		CollectionID:  j.CollectionId,
+		OwnerID:       j.OwnerId,
+		UserID:        j.UserId,
+		Format:        j.Format,
+		Options:       string(j.Options),
+		Status:        j.Status,
+		Attempts:      j.Attempts,
+		Error:         j.Error,
+		FileKey:       j.FileKey,
+		FileName:      j.FileName,
+		FileSize:      j.FileSize,
+		CreatedAt:     j.CreatedAt,
+		StartedAt:     j.StartedAt,
+		FinishedAt:    j.FinishedAt,
+		ExpiresAt:     j.ExpiresAt,
+		NextAttemptAt: j.NextAttemptAt,
+	}
+}
+
+type exportJobRepo struct {
+	db *pg.DB
+}
+
+type ExportJobs interface {
+	Create(job models.ExportJob) (*models.ExportJob, error)
+	GetById(id uint64) (*models.ExportJob, error)
+	GetByUserId(userId uint64) ([]models.ExportJob, error)
+	TakePending(startedAt time.Time) (*models.ExportJob, error)
+	ResetStale(startedBefore time.Time, maxAttempts uint64, expiresAt time.Time) error
+	GetExpired(now time.Time) ([]models.ExportJob, error)
+	Update(job models.ExportJob) error
+	DeleteById(id uint64) error
+}
+
+func NewExportJobsRepo(db *pg.DB) ExportJobs {
+	return &exportJobRepo{
+		db: db,
+	}
+}
+
+func (r *exportJobRepo) Create(job models.ExportJob) (*models.ExportJob, error) {
+	jobModel := ToExportJobModel(job)
+
+	_, err := r.db.Model(jobModel).Insert()
+	if err != nil {
+		return nil, err
+	}
+
+	createdJob := jobModel.FromModel()
+	return &createdJob, nil
+}
+
+func (r *exportJobRepo) GetById(id uint64) (*models.ExportJob, error) {
+	jobModel := ExportJobModel{}
+	err := r.db.Model(&jobModel).Where("id=?", id).First()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	job := jobModel.FromModel()
+	return &job, nil
+}
+
+func (r *exportJobRepo) GetByUserId(userId uint64) ([]models.ExportJob, error) {
+	var jobModels []ExportJobModel
+
+	err := r.db.Model(&jobModels).Where("user_id=?", userId).Order("created_at DESC").Select()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := []models.ExportJob{}
+	for _, j := range jobModels {
+		jobs = append(jobs, j.FromModel())
+	}
+
+	return jobs, nil
+}
+
+// TakePending marks the oldest pending job which can be attempted at startedAt as processing
+// and returns it, locked rows are skipped so several workers don't take the same job
+func (r *exportJobRepo) TakePending(startedAt time.Time) (*models.ExportJob, error) {
+	jobModel := ExportJobModel{}
+
+	_, err := r.db.QueryOne(&jobModel, `
+		UPDATE export_jobs SET status=?, started_at=?, attempts=attempts+1
+		WHERE id=(
+			SELECT id FROM export_jobs WHERE status=?
+			AND (next_attempt_at IS NULL OR next_attempt_at<=?)
+			ORDER BY created_at LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, models.ExportStatusProcessing, startedAt, models.ExportStatusPending, startedAt)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	job := jobModel.FromModel()
+	return &job, nil
+}
+
+// ResetStale returns jobs of stopped workers to the queue,
+// jobs without attempts left are failed and removed after expiresAt
+func (r *exportJobRepo) ResetStale(startedBefore time.Time, maxAttempts uint64, expiresAt time.Time) error {
+	_, err := r.db.Model(&ExportJobModel{}).
+		Set("status=?", models.ExportStatusPending).
+		Where("status=?", models.ExportStatusProcessing).
+		Where("started_at<?", startedBefore).
+		Where("attempts<?", maxAttempts).
+		Update()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Model(&ExportJobModel{}).
+		Set("status=?", models.ExportStatusFailed).
+		Set("error=?", "export was interrupted").
+		Set("finished_at=?", time.Now()).
+		Set("expires_at=?", expiresAt).
+		Where("status=?", models.ExportStatusProcessing).
+		Where("started_at<?", startedBefore).
+		Update()
+	return err
+}
+
+// GetExpired returns finished jobs which files and records can be removed
+func (r *exportJobRepo) GetExpired(now time.Time) ([]models.ExportJob, error) {
+	var jobModels []ExportJobModel
+
+	err := r.db.Model(&jobModels).Where("expires_at<?", now).Order("expires_at").Select()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := []models.ExportJob{}
+	for _, j := range jobModels {
+		jobs = append(jobs, j.FromModel())
+	}
+
+	return jobs, nil
+}
+
+func (r *exportJobRepo) Update(job models.ExportJob) error {
+	model := ToExportJobModel(job)
+
+	_, err := r.db.Model(model).
+		Where("id=?", model.ID).
+		Column("status", "attempts", "error", "file_key", "file_name", "file_size", "started_at", "finished_at", "expires_at", "next_attempt_at").
+		Update()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *exportJobRepo) DeleteById(id uint64) error {
+	_, err := r.db.Model(&ExportJobModel{}).Where("id=?", id).Delete()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
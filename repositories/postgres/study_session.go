@@ -0,0 +1,300 @@
+package postgres
+
+import (
+	"context"
+	"time"
+	"vacabulary/models"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type StudySessionModel struct {
+	tableName struct{} `pg:"study_sessions"`
+
+	ID             uint64                        `pg:"id"`
+	UserID         uint64                        `pg:"user_id"`
+	CollectionID   uint64                        `pg:"collection_id"`
+	Mode           string                        `pg:"mode"`
+	QuestionsCount uint64                        `pg:"questions_count"`
+	CorrectCount   uint64                        `pg:"correct_count"`
+	StartedAt      time.Time                     `pg:"started_at"`
+	FinishedAt     *time.Time                    `pg:"finished_at"`
+	Answers        []StudySessionAnswerModel     `pg:"rel:has-many,join_fk:session_id"`
+	Questions      []models.StudySessionQuestion `pg:"questions,type:jsonb"`
+}
+
+type AnswersPerDayModel struct {
+	Date  string `pg:"date"`
+	Count uint64 `pg:"count"`
+}
+
+type StudySessionAnswerModel struct {
+	tableName struct{} `pg:"study_session_answers"`
+
+	ID           uint64    `pg:"id"`
+	SessionID    uint64    `pg:"session_id"`
+	UserID       uint64    `pg:"user_id"`
+	CollectionID uint64    `pg:"collection_id"`
+	WordID       string    `pg:"word_id"`
+	Word         string    `pg:"word"`
+	Correct      bool      `pg:"correct,use_zero"`
+	AnsweredAt   time.Time `pg:"answered_at"`
+}
+
+type MissedWordModel struct {
+	WordID       string    `pg:"word_id"`
+	Word         string    `pg:"word"`
+	Mistakes     uint64    `pg:"mistakes"`
+	Attempts     uint64    `pg:"attempts"`
+	LastMissedAt time.Time `pg:"last_missed_at"`
+}
+
+func (s *StudySessionModel) FromModel() models.StudySession {
+	session := models.StudySession{
+		Id:             s.ID,
+		UserId:         s.UserID,
+		CollectionId:   s.CollectionID,
+		Mode:           s.Mode,
+		QuestionsCount: s.QuestionsCount,
+		CorrectCount:   s.CorrectCount,
+		StartedAt:      s.StartedAt,
+		FinishedAt:     s.FinishedAt,
+		Questions:      s.Questions,
+	}
+
+	for _, a := range s.Answers {
+		session.Answers = append(session.Answers, a.FromModel())
+	}
+
+	return session
+}
+
+func (a *StudySessionAnswerModel) FromModel() models.StudySessionAnswer {
+	return models.StudySessionAnswer{
+		Id:           a.ID,
+		SessionId:    a.SessionID,
+		UserId:       a.UserID,
+		CollectionId: a.CollectionID,
+		WordId:       a.WordID,
+		Word:         a.Word,
+		Correct:      a.Correct,
+		AnsweredAt:   a.AnsweredAt,
+	}
+}
+
+func (m *MissedWordModel) FromModel() models.MissedWord {
+	return models.MissedWord{
+		WordId:       m.WordID,
+		Word:         m.Word,
+		Mistakes:     m.Mistakes,
+		Attempts:     m.Attempts,
+		LastMissedAt: m.LastMissedAt,
+	}
+}
+
+func (m *AnswersPerDayModel) FromModel() models.AnswersPerDay {
+	return models.AnswersPerDay{
+		Date:  m.Date,
+		Count: m.Count,
+	}
+}
+
+func ToStudySessionModel(s models.StudySession) *StudySessionModel {
+	return &StudySessionModel{
+		ID:             s.Id,
+		UserID:         s.UserId,
+		CollectionID:   s.CollectionId,
+		Mode:           s.Mode,
+		QuestionsCount: s.QuestionsCount,
+		CorrectCount:   s.CorrectCount,
+		StartedAt:      s.StartedAt,
+		FinishedAt:     s.FinishedAt,
+		Questions:      s.Questions,
+	}
+}
+
+func ToStudySessionAnswerModel(a models.StudySessionAnswer) StudySessionAnswerModel {
+	return StudySessionAnswerModel{
+		ID:           a.Id,
+		SessionID:    a.SessionId,
+		UserID:       a.UserId,
+		CollectionID: a.CollectionId,
+		WordID:       a.WordId,
+		Word:         a.Word,
+		Correct:      a.Correct,
+		AnsweredAt:   a.AnsweredAt,
+	}
+}
+
+type studySessionRepo struct {
+	db *pg.DB
+}
+
+type StudySessions interface {
+	Create(session models.StudySession) (*models.StudySession, error)
+	GetById(id uint64) (*models.StudySession, error)
+	GetByUserId(userId uint64, collectionId uint64) ([]models.StudySession, error)
+	AddAnswers(sessionId uint64, answers []models.StudySessionAnswer) error
+	AddAnswer(answer models.StudySessionAnswer) error
+	Finish(id uint64, finishedAt time.Time) error
+	GetMostMissedWords(userId uint64, collectionId uint64, limit uint64) ([]models.MissedWord, error)
+	GetAnswersCountPerDay(userId uint64, from, to time.Time, timezone string) ([]models.AnswersPerDay, error)
+}
+
+func NewStudySessionsRepo(db *pg.DB) StudySessions {
+	return &studySessionRepo{
+		db: db,
+	}
+}
+
+func (r *studySessionRepo) Create(session models.StudySession) (*models.StudySession, error) {
+	sessionModel := ToStudySessionModel(session)
+
+	_, err := r.db.Model(sessionModel).Insert()
+	if err != nil {
+		return nil, err
+	}
+
+	createdSession := sessionModel.FromModel()
+	return &createdSession, nil
+}
+
+func (r *studySessionRepo) GetById(id uint64) (*models.StudySession, error) {
+	session := StudySessionModel{}
+	err := r.db.Model(&session).Relation("Answers", func(q *pg.Query) (*pg.Query, error) {
+		return q.Order("answered_at"), nil
+	}).Where("study_session_model.id=?", id).First()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	findedSession := session.FromModel()
+	return &findedSession, nil
+}
+
+// GetByUserId returns user sessions, collectionId equal to 0 means all collections
+func (r *studySessionRepo) GetByUserId(userId uint64, collectionId uint64) ([]models.StudySession, error) {
+	var sessionModels []StudySessionModel
+
+	query := r.db.Model(&sessionModels).Where("user_id=?", userId)
+	if collectionId != 0 {
+		query = query.Where("collection_id=?", collectionId)
+	}
+
+	err := query.Order("started_at DESC").Select()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []models.StudySession{}
+	for _, s := range sessionModels {
+		sessions = append(sessions, s.FromModel())
+	}
+
+	return sessions, nil
+}
+
+func (r *studySessionRepo) AddAnswers(sessionId uint64, answers []models.StudySessionAnswer) error {
+	if len(answers) == 0 {
+		return nil
+	}
+
+	var answerModels []StudySessionAnswerModel
+	var correctCount int
+	for _, a := range answers {
+		a.SessionId = sessionId
+		answerModels = append(answerModels, ToStudySessionAnswerModel(a))
+
+		if a.Correct {
+			correctCount++
+		}
+	}
+
+	return r.db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		_, err := tx.Model(&answerModels).Insert()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Model(&StudySessionModel{}).
+			Set("questions_count = questions_count + ?", len(answerModels)).
+			Set("correct_count = correct_count + ?", correctCount).
+			Where("id=?", sessionId).
+			Update()
+		return err
+	})
+}
+
+// AddAnswer stores answer of review made without session, so it is counted in activity of the user
+func (r *studySessionRepo) AddAnswer(answer models.StudySessionAnswer) error {
+	answerModel := ToStudySessionAnswerModel(answer)
+
+	_, err := r.db.Model(&answerModel).Insert()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *studySessionRepo) Finish(id uint64, finishedAt time.Time) error {
+	_, err := r.db.Model(&StudySessionModel{}).Set("finished_at=?", finishedAt).Where("id=?", id).Update()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *studySessionRepo) GetMostMissedWords(userId uint64, collectionId uint64, limit uint64) ([]models.MissedWord, error) {
+	var missedWordModels []MissedWordModel
+
+	_, err := r.db.Query(&missedWordModels, `
+		SELECT a.word_id,
+			(array_agg(a.word ORDER BY a.answered_at DESC))[1] AS word,
+			count(*) FILTER (WHERE NOT a.correct) AS mistakes,
+			count(*) AS attempts,
+			max(a.answered_at) FILTER (WHERE NOT a.correct) AS last_missed_at
+		FROM study_session_answers a
+		WHERE a.user_id = ? AND a.collection_id = ?
+		GROUP BY a.word_id
+		HAVING count(*) FILTER (WHERE NOT a.correct) > 0
+		ORDER BY mistakes DESC, last_missed_at DESC
+		LIMIT ?`, userId, collectionId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	missedWords := []models.MissedWord{}
+	for _, m := range missedWordModels {
+		missedWords = append(missedWords, m.FromModel())
+	}
+
+	return missedWords, nil
+}
+
+// GetAnswersCountPerDay returns answers count of sessions and reviews per day, days are bucketed in the passed timezone
+func (r *studySessionRepo) GetAnswersCountPerDay(userId uint64, from, to time.Time, timezone string) ([]models.AnswersPerDay, error) {
+	var answersPerDayModels []AnswersPerDayModel
+
+	_, err := r.db.Query(&answersPerDayModels, `
+		SELECT to_char(a.answered_at AT TIME ZONE ?, 'YYYY-MM-DD') AS date,
+			count(*) AS count
+		FROM study_session_answers a
+		WHERE a.user_id = ? AND a.answered_at >= ? AND a.answered_at <= ?
+		GROUP BY 1
+		ORDER BY 1`, timezone, userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	answersPerDay := []models.AnswersPerDay{}
+	for _, a := range answersPerDayModels {
+		answersPerDay = append(answersPerDay, a.FromModel())
+	}
+
+	return answersPerDay, nil
+}
@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"time"
+	"vacabulary/models"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type TranslationJobModel struct {
+	tableName struct{} `pg:"translation_jobs"`
+
+	ID            uint64     `pg:"id"`
+	CollectionID  uint64     `pg:"collection_id"`
+	OwnerID       uint64     `pg:"owner_id"`
+	UserID        uint64     `pg:"user_id"`
+	Words         []string   `pg:"words,array"`
+	Status        string     `pg:"status"`
+	Attempts      uint64     `pg:"attempts,use_zero"`
+	Translated    uint64     `pg:"translated,use_zero"`
+	Error         string     `pg:"error,use_zero"`
+	CreatedAt     time.Time  `pg:"created_at"`
+	StartedAt     *time.Time `pg:"started_at"`
+	FinishedAt    *time.Time `pg:"finished_at"`
+	NextAttemptAt *time.Time `pg:"next_attempt_at"`
+}
+
+func (m *TranslationJobModel) FromModel() models.TranslationJob {
+	return models.TranslationJob{
+		Id:            m.ID,
+		CollectionId:  m.CollectionID,
+		OwnerId:       m.OwnerID,
+		UserId:        m.UserID,
+		Words:         m.Words,
+		Status:        m.Status,
+		Attempts:      m.Attempts,
+		Translated:    m.Translated,
+		Error:         m.Error,
+		CreatedAt:     m.CreatedAt,
+		StartedAt:     m.StartedAt,
+		FinishedAt:    m.FinishedAt,
+		NextAttemptAt: m.NextAttemptAt,
+	}
+}
+
+func ToTranslationJobModel(j models.TranslationJob) *TranslationJobModel {
+	return &TranslationJobModel{
+		ID:            j.Id,
+		CollectionID:  j.CollectionId,
+		OwnerID:       j.OwnerId,
+		UserID:        j.UserId,
+		Words:         j.Words,
+		Status:        j.Status,
+		Attempts:      j.Attempts,
+		Translated:    j.Translated,
+		Error:         j.Error,
+		CreatedAt:     j.CreatedAt,
+		StartedAt:     j.StartedAt,
+		FinishedAt:    j.FinishedAt,
+		NextAttemptAt: j.NextAttemptAt,
+	}
+}
+
+type translationJobRepo struct {
+	db *pg.DB
+}
+
+type TranslationJobs interface {
+	Create(job models.TranslationJob) (*models.TranslationJob, error)
+	GetById(id uint64) (*models.TranslationJob, error)
+	TakePending(startedAt time.Time) (*models.TranslationJob, error)
+	ResetStale(startedBefore time.Time, maxAttempts uint64) error
+	Update(job models.TranslationJob) error
+	DeleteFinished(finishedBefore time.Time) error
+}
+
+func NewTranslationJobsRepo(db *pg.DB) TranslationJobs {
+	return &translationJobRepo{
+		db: db,
+	}
+}
+
+func (r *translationJobRepo) Create(job models.TranslationJob) (*models.TranslationJob, error) {
+	jobModel := ToTranslationJobModel(job)
+
+	_, err := r.db.Model(jobModel).Insert()
+	if err != nil {
+		return nil, err
+	}
+
+	createdJob := jobModel.FromModel()
+	return &createdJob, nil
+}
+
+func (r *translationJobRepo) GetById(id uint64) (*models.TranslationJob, error) {
+	jobModel := TranslationJobModel{}
+	err := r.db.Model(&jobModel).Where("id=?", id).First()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	job := jobModel.FromModel()
+	return &job, nil
+}
+
+// TakePending marks the oldest pending job which can be attempted at startedAt as processing
+// and returns it, locked rows are skipped so several workers don't take the same job
+func (r *translationJobRepo) TakePending(startedAt time.Time) (*models.TranslationJob, error) {
+	jobModel := TranslationJobModel{}
+
+	_, err := r.db.QueryOne(&jobModel, `
+		UPDATE translation_jobs SET status=?, started_at=?, attempts=attempts+1
+		WHERE id=(
+			SELECT id FROM translation_jobs WHERE status=?
+			AND (next_attempt_at IS NULL OR next_attempt_at<=?)
+			ORDER BY created_at LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, models.TranslationStatusProcessing, startedAt, models.TranslationStatusPending, startedAt)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	job := jobModel.FromModel()
+	return &job, nil
+}
+
+// ResetStale returns jobs of stopped workers to the queue, jobs without attempts left are failed
+func (r *translationJobRepo) ResetStale(startedBefore time.Time, maxAttempts uint64) error {
+	_, err := r.db.Model(&TranslationJobModel{}).
+		Set("status=?", models.TranslationStatusPending).
+		Where("status=?", models.TranslationStatusProcessing).
+		Where("started_at<?", startedBefore).
+		Where("attempts<?", maxAttempts).
+		Update()
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Model(&TranslationJobModel{}).
+		Set("status=?", models.TranslationStatusFailed).
+		Set("error=?", "translation was interrupted").
+		Set("finished_at=?", time.Now()).
+		Where("status=?", models.TranslationStatusProcessing).
+		Where("started_at<?", startedBefore).
+		Update()
+	return err
+}
+
+func (r *translationJobRepo) Update(job models.TranslationJob) error {
+	model := ToTranslationJobModel(job)
+
+	_, err := r.db.Model(model).
+		Where("id=?", model.ID).
+		Column("status", "attempts", "translated", "error", "started_at", "finished_at", "next_attempt_at").
+		Update()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteFinished removes completed and failed jobs, their result is not needed after import
+func (r *translationJobRepo) DeleteFinished(finishedBefore time.Time) error {
+	_, err := r.db.Model(&TranslationJobModel{}).
+		Where("status IN (?)", pg.In([]string{models.TranslationStatusCompleted, models.TranslationStatusFailed})).
+		Where("finished_at<?", finishedBefore).
+		Delete()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -22,9 +22,11 @@ type UserModel struct {
 type UserSettingsModel struct {
 	tableName struct{} `pg:"user_settings"`
 
-	ID       uint64 `pg:"id"`
-	UserID   uint64 `pg:"user_id"`
-	Language string `pg:"app_language"`
+	ID        uint64 `pg:"id"`
+	UserID    uint64 `pg:"user_id"`
+	Language  string `pg:"app_language"`
+	Timezone  string `pg:"timezone"`
+	DailyGoal uint64 `pg:"daily_goal"`
 }
 
 func (u *UserModel) FromModel() models.User {
@@ -47,9 +49,11 @@ func (u *UserModel) FromModel() models.User {
 
 func (u *UserSettingsModel) FromModel() models.UserSettings {
 	return models.UserSettings{
-		Id:       u.ID,
-		UserId:   u.UserID,
-		Language: u.Language,
+		Id:        u.ID,
+		UserId:    u.UserID,
+		Language:  u.Language,
+		Timezone:  u.Timezone,
+		DailyGoal: u.DailyGoal,
 	}
 }
 
@@ -66,9 +70,11 @@ func ToUserModel(u models.User) *UserModel {
 
 func ToUserSettingsModel(u models.UserSettings) *UserSettingsModel {
 	return &UserSettingsModel{
-		ID:       u.Id,
-		UserID:   u.UserId,
-		Language: u.Language,
+		ID:        u.Id,
+		UserID:    u.UserId,
+		Language:  u.Language,
+		Timezone:  u.Timezone,
+		DailyGoal: u.DailyGoal,
 	}
 }
 
@@ -80,10 +86,13 @@ type Users interface {
 	Create(user models.User) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
 	GetById(id uint64) (*models.User, error)
+	GetByIds(ids []uint64) ([]models.User, error)
 	GetAll() ([]models.User, error)
 	GetByCollectionId(id uint64) (*models.User, error)
 
 	UpdateUserLanguage(language string, userId uint64) error
+	UpdateUserTimezone(timezone string, userId uint64) error
+	UpdateUserDailyGoal(dailyGoal uint64, userId uint64) error
 }
 
 func NewUsersRepo(db *pg.DB) Users {
@@ -150,6 +159,26 @@ func (r *userRepo) GetById(id uint64) (*models.User, error) {
 	return &createdUser, nil
 }
 
+// GetByIds returns users found by ids, missing users are skipped
+func (r *userRepo) GetByIds(ids []uint64) ([]models.User, error) {
+	if len(ids) == 0 {
+		return []models.User{}, nil
+	}
+
+	var users []UserModel
+	err := r.db.Model(&users).Where("user_model.id IN (?)", pg.In(ids)).Select()
+	if err != nil {
+		return nil, err
+	}
+
+	usersRes := []models.User{}
+	for _, u := range users {
+		usersRes = append(usersRes, u.FromModel())
+	}
+
+	return usersRes, nil
+}
+
 func (r *userRepo) GetAll() ([]models.User, error) {
 	var users []UserModel
 	err := r.db.Model(&users).Select()
@@ -180,3 +209,29 @@ func (r *userRepo) UpdateUserLanguage(language string, userId uint64) error {
 
 	return nil
 }
+
+func (r *userRepo) UpdateUserTimezone(timezone string, userId uint64) error {
+	settings := UserSettingsModel{Timezone: timezone}
+	_, err := r.db.Model(&settings).Column("timezone").Where("user_id=?", userId).Update()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepo) UpdateUserDailyGoal(dailyGoal uint64, userId uint64) error {
+	settings := UserSettingsModel{DailyGoal: dailyGoal}
+	_, err := r.db.Model(&settings).Column("daily_goal").Where("user_id=?", userId).Update()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
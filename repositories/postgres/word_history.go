@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"time"
+	"vacabulary/models"
+
+	"github.com/go-pg/pg/v10"
+)
+
+type WordHistoryModel struct {
+	tableName struct{} `pg:"word_history"`
+
+	ID           uint64              `pg:"id"`
+	WordID       string              `pg:"word_id"`
+	CollectionID uint64              `pg:"collection_id"`
+	OwnerID      uint64              `pg:"owner_id"`
+	ActorID      uint64              `pg:"actor_id"`
+	Action       string              `pg:"action"`
+	RevertedFrom *uint64             `pg:"reverted_from"`
+	Changes      []models.WordChange `pg:"changes,type:jsonb"`
+	Snapshot     models.WordSnapshot `pg:"snapshot,type:jsonb"`
+	CreatedAt    time.Time           `pg:"created_at"`
+	Actor        *UserModel          `pg:"rel:has-one,fk:actor_id"`
+}
+
+func (m *WordHistoryModel) FromModel() models.WordHistory {
+	history := models.WordHistory{
+		Id:           m.ID,
+		WordId:       m.WordID,
+		CollectionId: m.CollectionID,
+		OwnerId:      m.OwnerID,
+		ActorId:      m.ActorID,
+		Action:       m.Action,
+		RevertedFrom: m.RevertedFrom,
+		Changes:      m.Changes,
+		Snapshot:     m.Snapshot,
+		CreatedAt:    m.CreatedAt,
+	}
+
+	if history.Changes == nil {
+		history.Changes = []models.WordChange{}
+	}
+
+	if m.Actor != nil {
+		actor := m.Actor.FromModel()
+		history.Actor = &actor
+	}
+
+	return history
+}
+
+func ToWordHistoryModel(h models.WordHistory) *WordHistoryModel {
+	return &WordHistoryModel{
+		ID:           h.Id,
+		WordID:       h.WordId,
+		CollectionID: h.CollectionId,
+		OwnerID:      h.OwnerId,
+		ActorID:      h.ActorId,
+		Action:       h.Action,
+		RevertedFrom: h.RevertedFrom,
+		Changes:      h.Changes,
+		Snapshot:     h.Snapshot,
+		CreatedAt:    h.CreatedAt,
+	}
+}
+
+type wordHistoryRepo struct {
+	db *pg.DB
+}
+
+type WordHistories interface {
+	Create(history models.WordHistory) (*models.WordHistory, error)
+	GetById(id uint64) (*models.WordHistory, error)
+	GetByWordId(wordId string) ([]models.WordHistory, error)
+	DeleteByWordId(wordId string) error
+	DeleteByWordIds(wordIds []string) error
+	UpdateCollectionId(wordId string, collectionId uint64) error
+}
+
+func NewWordHistoriesRepo(db *pg.DB) WordHistories {
+	return &wordHistoryRepo{
+		db: db,
+	}
+}
+
+func (r *wordHistoryRepo) Create(history models.WordHistory) (*models.WordHistory, error) {
+	historyModel := ToWordHistoryModel(history)
+
+	_, err := r.db.Model(historyModel).Insert()
+	if err != nil {
+		return nil, err
+	}
+
+	createdHistory := historyModel.FromModel()
+	return &createdHistory, nil
+}
+
+func (r *wordHistoryRepo) GetById(id uint64) (*models.WordHistory, error) {
+	historyModel := WordHistoryModel{}
+	err := r.db.Model(&historyModel).Where("id=?", id).First()
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	history := historyModel.FromModel()
+	return &history, nil
+}
+
+// GetByWordId returns changes of the word with their actors, the latest change first
+func (r *wordHistoryRepo) GetByWordId(wordId string) ([]models.WordHistory, error) {
+	var historyModels []WordHistoryModel
+
+	err := r.db.Model(&historyModels).
+		Relation("Actor").
+		Where("word_history_model.word_id=?", wordId).
+		Order("word_history_model.created_at DESC", "word_history_model.id DESC").
+		Select()
+	if err != nil {
+		return nil, err
+	}
+
+	histories := []models.WordHistory{}
+	for _, h := range historyModels {
+		histories = append(histories, h.FromModel())
+	}
+
+	return histories, nil
+}
+
+func (r *wordHistoryRepo) DeleteByWordId(wordId string) error {
+	_, err := r.db.Model(&WordHistoryModel{}).Where("word_id=?", wordId).Delete()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *wordHistoryRepo) DeleteByWordIds(wordIds []string) error {
+	if len(wordIds) == 0 {
+		return nil
+	}
+
+	_, err := r.db.Model(&WordHistoryModel{}).Where("word_id IN (?)", pg.In(wordIds)).Delete()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateCollectionId keeps history of the word moved to another collection
+func (r *wordHistoryRepo) UpdateCollectionId(wordId string, collectionId uint64) error {
+	_, err := r.db.Model(&WordHistoryModel{}).Set("collection_id=?", collectionId).Where("word_id=?", wordId).Update()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}